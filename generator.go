@@ -0,0 +1,175 @@
+// Package goproject generates Go projects from a profile, as the goproject command does, for use as a library.
+package goproject
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+
+	"github.com/bytemare/goproject/internal/templates"
+
+	"github.com/pkg/errors"
+)
+
+// Hooks are called at the steps of a generation. A hook returning an error stops the generation.
+type Hooks struct {
+	// BeforeBuild is called with the plan of the generation, before anything is written.
+	// It may change the plan, e.g. to skip some files.
+	BeforeBuild func(ctx context.Context, plan *Plan) error
+
+	// AfterBuild is called with the report of the generation, once the project was written
+	AfterBuild func(ctx context.Context, report *Report) error
+}
+
+// Options tune a generation. The zero value generates the project in a directory named after it, in the current
+// directory, and skips the files that already exist.
+type Options struct {
+	// Dir is the project directory, the project name in the current directory if empty
+	Dir string
+
+	// Module is the go module path, derived from the git URL of the profile and the project name if empty
+	Module string
+
+	// Vars are added to the template variables of the profile, replacing those of the same name
+	Vars map[string]interface{}
+
+	// OnConflict is the policy for files that already exist, unless their layout entry sets one. It takes precedence
+	// over the policy of the profile's layout.
+	OnConflict string
+
+	// ConflictStyle is how merges leave conflicts, markers by default
+	ConflictStyle string
+
+	// Confirm is asked whether to overwrite the files under the prompt policy. If nil, they are skipped.
+	Confirm func(question string) (bool, error)
+
+	// DryRun tells to only return the report of what the generation would do, without writing anything
+	DryRun bool
+
+	// KeepGoing tells to write the steps that succeeded even if others failed
+	KeepGoing bool
+
+	// Jobs is the number of templates rendered and written concurrently, the number of CPUs if not set
+	Jobs int
+
+	// Out receives the progress of the generation, which is discarded if nil
+	Out io.Writer
+
+	// FS is the file system the project is generated into, the disk if nil. Commands, such as git init, run on the disk
+	// nonetheless, in a temporary directory.
+	FS FS
+
+	// Hooks are called at the steps of the generation
+	Hooks Hooks
+}
+
+// Generator generates projects from a profile
+type Generator struct {
+	profile *Profile
+	options Options
+}
+
+// NewGenerator returns a Generator of projects for the profile, with the given options
+func NewGenerator(profile *Profile, options Options) (*Generator, error) {
+	if profile == nil {
+		return nil, errors.New("no profile given")
+	}
+
+	if profile.profile == nil {
+		return nil, errors.New("profile has no configuration, it must come from ParseProfile or LoadProfile")
+	}
+
+	if options.OnConflict != "" {
+		if err := templates.CheckOnConflict(options.OnConflict); err != nil {
+			return nil, err
+		}
+	}
+
+	switch options.ConflictStyle {
+	case "", ConflictMarkers, ConflictFile:
+	default:
+		return nil, errors.Errorf("invalid conflict style '%s', expected %s or %s",
+			options.ConflictStyle, ConflictMarkers, ConflictFile)
+	}
+
+	return &Generator{
+		profile: profile,
+		options: options,
+	}, nil
+}
+
+// Generate generates the project of the given name, and returns the report of the generation.
+// If a step fails, the returned error is a *BuildError listing the failed steps, and nothing is written unless the
+// generator keeps going. If the context is done before the project is moved into place, nothing is written.
+func (g *Generator) Generate(ctx context.Context, name string) (*Report, error) {
+	project, err := g.project(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	plan, err := project.Plan()
+	if err != nil {
+		return nil, err
+	}
+
+	if g.options.Hooks.BeforeBuild != nil {
+		if err := g.options.Hooks.BeforeBuild(ctx, plan); err != nil {
+			return plan.Report(name), errors.Wrap(err, "nothing was written")
+		}
+	}
+
+	if g.options.DryRun {
+		return plan.Report(name), nil
+	}
+
+	report, err := project.BuildPlan(ctx, plan)
+	if err != nil {
+		return report, err
+	}
+
+	if g.options.Hooks.AfterBuild != nil {
+		if err := g.options.Hooks.AfterBuild(ctx, report); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// project returns the project of the given name, set up with the options of the generator
+func (g *Generator) project(name string) (*templates.Project, error) {
+	if name == "" {
+		return nil, errors.New("no project name given")
+	}
+
+	project, err := templates.NewProject(g.profile.profile, name, ".", g.options.Vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if g.options.Dir != "" {
+		project.Path = g.options.Dir
+	}
+
+	if g.options.Module != "" {
+		project.Module = g.options.Module
+	}
+
+	project.OnConflict = g.options.OnConflict
+	project.ConflictStyle = g.options.ConflictStyle
+	project.Confirm = g.options.Confirm
+	project.KeepGoing = g.options.KeepGoing
+	project.Jobs = g.options.Jobs
+	project.FS = g.options.FS
+
+	project.Out = g.options.Out
+	if project.Out == nil {
+		project.Out = ioutil.Discard
+	}
+
+	return project, nil
+}
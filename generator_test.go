@@ -0,0 +1,141 @@
+package goproject_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bytemare/goproject"
+)
+
+const profile = `
+[author]
+name = "Bytemare"
+contact = "dev@bytema.re"
+
+[layout]
+directories = ["cmd"]
+files = ["notes"]
+
+[templates.notes]
+filename = "NOTES.md"
+template = "# {{.Project.Name}}, by {{.Vars.team}}\n"
+
+[vars]
+team = "core"
+`
+
+// withoutCommands skips the commands of the generation, which run on the disk
+func withoutCommands(ctx context.Context, plan *goproject.Plan) error {
+	plan.Commands = nil
+	return nil
+}
+
+func ExampleGenerator_Generate() {
+	profile, err := goproject.ParseProfile("service", []byte(profile))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fs := goproject.NewMemory()
+
+	generator, err := goproject.NewGenerator(profile, goproject.Options{
+		Dir:   "/myApp",
+		FS:    fs,
+		Hooks: goproject.Hooks{BeforeBuild: withoutCommands},
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	report, err := generator.Generate(context.Background(), "myApp")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, r := range report.Records {
+		fmt.Println(r.Type, r.Status, r.Path)
+	}
+
+	notes, _ := fs.ReadFile("/myApp/NOTES.md")
+	fmt.Print(string(notes))
+
+	// Output:
+	// directory created cmd
+	// file created NOTES.md
+	// # myApp, by core
+}
+
+// TestLoadProfile loads a profile and generates a project as a library does, without the command line initialising
+// the configuration
+func TestLoadProfile(t *testing.T) {
+	home, err := ioutil.TempDir("", "goproject-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	xdg, set := os.LookupEnv("XDG_CONFIG_HOME")
+	defer func() {
+		if set {
+			os.Setenv("XDG_CONFIG_HOME", xdg)
+		} else {
+			os.Unsetenv("XDG_CONFIG_HOME")
+		}
+	}()
+
+	if err := os.Setenv("XDG_CONFIG_HOME", home); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles := filepath.Join(home, "goproject", "profiles")
+	if err := os.MkdirAll(profiles, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(profiles, "service.toml"), []byte(profile), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := goproject.LoadProfile("service.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := goproject.NewMemory()
+
+	generator, err := goproject.NewGenerator(p, goproject.Options{
+		Dir:   "/myApp",
+		FS:    fs,
+		Vars:  map[string]interface{}{"team": "tools"},
+		Hooks: goproject.Hooks{BeforeBuild: withoutCommands},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := generator.Generate(context.Background(), "myApp"); err != nil {
+		t.Fatal(err)
+	}
+
+	notes, err := fs.ReadFile("/myApp/NOTES.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "# myApp, by tools\n"; string(notes) != want {
+		t.Errorf("got %q, want %q", notes, want)
+	}
+}
+
+func TestNewGenerator(t *testing.T) {
+	if _, err := goproject.NewGenerator(&goproject.Profile{}, goproject.Options{}); err == nil {
+		t.Error("a profile that was neither parsed nor loaded was accepted")
+	}
+}
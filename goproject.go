@@ -0,0 +1,111 @@
+// Package goproject generates Go projects from a profile, as the goproject command does, for use as a library.
+//
+// A Generator renders the layout and templates of a profile, and writes the project as the command does : everything
+// is staged first, and only moved into the project directory if all steps succeeded. Nothing is printed, the outcome
+// of each step is returned in a Report.
+package goproject
+
+import (
+	"github.com/bytemare/goproject/internal/config"
+	"github.com/bytemare/goproject/internal/templates"
+	"github.com/bytemare/goproject/internal/vfs"
+)
+
+// Profile describes the author, the layout, the templates and the values of the projects to generate.
+// Profiles are returned by ParseProfile and LoadProfile, which need no prior initialisation of goproject.
+type Profile struct {
+	profile *config.Profile
+}
+
+// Name returns the name of the profile
+func (p *Profile) Name() string {
+	return p.profile.Name
+}
+
+type (
+	// Plan holds what generating a project would do : the directories and files to create, and the commands to run
+	Plan = templates.Plan
+
+	// Report is the outcome of a generation, with one record per directory, file and command
+	Report = templates.Report
+
+	// Record is the outcome of a step of a generation
+	Record = templates.Record
+
+	// BuildError lists the steps of a generation that failed
+	BuildError = templates.BuildError
+
+	// Failure is a step of a generation that failed
+	Failure = templates.Failure
+
+	// FS is a file system projects are generated into
+	FS = vfs.FS
+
+	// OS is the file system of the disk
+	OS = vfs.OS
+
+	// Memory is a file system held in memory
+	Memory = vfs.Memory
+
+	// Overlay is a file system reading through to another one, and holding all changes in memory
+	Overlay = vfs.Overlay
+)
+
+// Policies for rendered files that already exist in the project
+const (
+	OnConflictSkip      = templates.OnConflictSkip
+	OnConflictOverwrite = templates.OnConflictOverwrite
+	OnConflictBackup    = templates.OnConflictBackup
+	OnConflictPrompt    = templates.OnConflictPrompt
+	OnConflictFail      = templates.OnConflictFail
+	OnConflictMerge     = templates.OnConflictMerge
+)
+
+// Styles of the conflicts left by merges : markers in the file, or the rendered file written aside
+const (
+	ConflictMarkers = templates.ConflictMarkers
+	ConflictFile    = templates.ConflictFile
+)
+
+// Statuses of the records of a report
+const (
+	StatusCreated     = templates.StatusCreated
+	StatusOverwritten = templates.StatusOverwritten
+	StatusMerged      = templates.StatusMerged
+	StatusSkipped     = templates.StatusSkipped
+	StatusRan         = templates.StatusRan
+	StatusFailed      = templates.StatusFailed
+	StatusNotWritten  = templates.StatusNotWritten
+)
+
+// ParseProfile parses the toml content of a profile, as found in the goproject configuration directory.
+// Only the templates and partials declared in the profile itself are available to the generation.
+func ParseProfile(name string, content []byte) (*Profile, error) {
+	p, err := config.ParseProfile(name, content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Profile{profile: p}, nil
+}
+
+// LoadProfile loads a profile of the user's goproject configuration directory, along with their templates and partials
+func LoadProfile(name string) (*Profile, error) {
+	p, err := config.LoadProfile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Profile{profile: p}, nil
+}
+
+// NewMemory returns an empty file system held in memory, to generate projects without touching the disk
+func NewMemory() *Memory {
+	return vfs.NewMemory()
+}
+
+// NewOverlay returns a file system reading through to the base, and holding all changes in memory. Generating into an
+// overlay of the disk previews the generation of a project, existing files included, without touching the disk.
+func NewOverlay(base FS) *Overlay {
+	return vfs.NewOverlay(base)
+}
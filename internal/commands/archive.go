@@ -0,0 +1,62 @@
+// Package commands holds the different CLI commands
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bytemare/goproject/internal/config"
+	"github.com/bytemare/goproject/internal/templates"
+	"github.com/bytemare/goproject/internal/vfs"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// setupArchive validates and sets the archive to write the project into
+func setupArchive(cmd *cobra.Command) error {
+	archive := cmd.Flag("archive").Value.String()
+	if archive == "" {
+		viper.Set("archive", "")
+		return nil
+	}
+
+	if _, err := vfs.ArchiveFormat(archive); err != nil {
+		return fmt.Errorf("error : %v", err)
+	}
+
+	if archive == "-" && viper.GetString("report") != "" && viper.GetString("report-file") == "" {
+		return fmt.Errorf("error : the archive and the report can't both be written to the standard output, " +
+			"use --report-file")
+	}
+
+	viper.Set("archive", archive)
+
+	return nil
+}
+
+// writeArchive writes the project built in memory into the archive file, or to the standard output for -
+func writeArchive(project *templates.Project, name string) error {
+	format, err := vfs.ArchiveFormat(name)
+	if err != nil {
+		return err
+	}
+
+	if name == "-" {
+		return project.Archive(os.Stdout, format)
+	}
+
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, config.FileMode)
+	if err != nil {
+		return fmt.Errorf("error : could not create archive '%s' : %v", name, err)
+	}
+
+	if err := project.Archive(f, format); err != nil {
+		_ = f.Close()
+		_ = os.Remove(name)
+
+		return fmt.Errorf("error : could not write archive '%s' : %v", name, err)
+	}
+
+	return f.Close()
+}
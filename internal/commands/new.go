@@ -2,11 +2,16 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/bytemare/goproject/internal/config"
 	"github.com/bytemare/goproject/internal/templates"
+	"github.com/bytemare/goproject/internal/vfs"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -29,42 +34,256 @@ and create the files and directories as specified in the profile.
 ./goproject new myApp -p myProfile
 
 Will do the same but with the specified profile
+
+./goproject new myApp --var license=MIT --var opensource=true
+
+Will add the variables to those of the profile's [vars] table, available to all templates as .Vars
+
+./goproject new myApp --module example.com/team/myApp
+
+Will use the given go module path, rather than the one derived from the profile's git URL and the project name.
+If the project already has a go.mod, its module path is used.
+
+./goproject new
+
+When run in a terminal without arguments nor profile, or with --interactive, a wizard asks for the project name,
+module path and profile, the layout entries to create, and the template variables the profile leaves empty.
+
+./goproject new myApp --dry-run --diff
+
+Will print the directories and files to create or skip, and the commands to run, without touching the disk.
+--show-content adds the rendered content of the files to create, and --diff the differences with existing files.
+
+./goproject new myApp --on-conflict backup
+
+Will back up the files that already exist with a timestamp suffix, and overwrite them. By default they are skipped.
+The other policies are overwrite, prompt to show the differences and ask, fail to abort before anything is written,
+and merge. The policy can also be set in the profile's [layout] table, or for a single file of the layout.
+
+./goproject new myApp --on-conflict merge
+
+Will merge the rendered files into those that already exist. Changes made on only one side are applied, and
+conflicting changes are left between conflict markers, or with --conflict-style file, the rendered file is written
+next to the existing one with a .goproject-new suffix. A copy of each merged file is kept in .goproject/base, as
+the base of later merges. The .gitignore template ignores the .goproject directory.
+
+./goproject new myApp --keep-going
+
+If a template can't be rendered or a command fails, nothing is written, and all failed steps are listed.
+With --keep-going, what could be built is written nonetheless. In both cases, the command exits with an error.
+
+./goproject new myApp --report json
+
+Will print a report of the build as json, with one record per directory, file and command : its status, path,
+template, the hash of its content and any error. The progress is then printed on the standard error.
+With --report-file, the report is written to the given file instead.
+
+./goproject new myApp --archive myApp.tar.gz
+
+Will write the project into a tar.gz archive, or a zip archive for a .zip file, instead of a directory.
+With --archive -, the tar.gz archive is written to the standard output. Nothing else is written to the disk.
 `,
 		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			setupNewProject(cmd, args)
+			if err := setupNewProject(cmd, args); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
 
 			if err := checkVars(); err != nil {
 				fmt.Println(err)
 				os.Exit(1)
 			}
 
-			newProject()
+			// Only run the wizard if there is someone to answer it
+			interactive, _ := cmd.Flags().GetBool("interactive")
+			if !interactive && len(args) == 0 && cmd.Flag("profile").Value.String() == "" {
+				interactive = isTerminal(os.Stdin)
+			}
+
+			if err := newProject(interactive); err != nil {
+				fmt.Fprintln(output(), err)
+				os.Exit(1)
+			}
 		},
 	}
 
-	newCmd.Flags().StringP("profile", "p", "", "Specify the profile you want to use")
+	newCmd.Flags().BoolP("interactive", "i", false, "Walk through the project creation with a wizard")
+	newCmd.Flags().String("module", "", "Go module path of the project (default the profile's git URL and the project name)")
+	newCmd.Flags().String("archive", "",
+		"Write the project into a .tar.gz or .zip archive instead of a directory (- for a tar.gz on the standard output)")
+	buildFlags(newCmd)
 
 	return newCmd
 }
 
-func setupNewProject(cmd *cobra.Command, args []string) {
+// buildFlags adds the flags of the commands building a project
+func buildFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("profile", "p", "", "Specify the profile you want to use")
+	cmd.Flags().StringArray("var", nil, "Set a template variable, as key=value (can be repeated)")
+	cmd.Flags().Bool("dry-run", false, "Print what would be done, without touching the disk")
+	cmd.Flags().Bool("show-content", false, "With --dry-run, print the rendered content of the files to create")
+	cmd.Flags().Bool("diff", false, "With --dry-run, print the differences between existing and rendered files")
+	cmd.Flags().String("on-conflict", "",
+		"What to do with files that already exist : "+strings.Join(templates.OnConflictPolicies(), ", ")+
+			" (default skip, or the profile's)")
+	cmd.Flags().String("conflict-style", templates.ConflictMarkers,
+		"How merges leave conflicts : markers in the file, or the rendered file written aside")
+	cmd.Flags().Bool("keep-going", false, "Write what could be built even if some steps failed")
+	cmd.Flags().Int("jobs", 0, "Number of templates rendered and written concurrently (default the number of CPUs)")
+	cmd.Flags().String("report", "", "Print a report of the build in the given format : json")
+	cmd.Flags().String("report-file", "", "Write the report of the build to the given file (implies --report json)")
+}
+
+func setupNewProject(cmd *cobra.Command, args []string) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("unable to get working directory : %v", err)
+	}
+
 	// If no argument was given, we develop the project inside the current directory, thus inheriting its name
-	if len(args) == 0 {
-		wd, err := os.Getwd()
-		if err != nil {
-			fmt.Printf("Unable to get working directory : %s\n", err)
-			os.Exit(1)
-		}
+	name := filepath.Base(wd)
+	if len(args) != 0 {
+		name = args[0]
+	}
+
+	setName(name, wd)
 
-		viper.Set("name", wd)
-	} else {
-		viper.Set("name", args[0])
+	module := cmd.Flag("module").Value.String()
+	if strings.ContainsAny(module, " \t\n\"'`") {
+		return fmt.Errorf("error : invalid module path '%s'", module)
 	}
 
+	viper.Set("module", module)
+
+	if err := setupBuild(cmd); err != nil {
+		return err
+	}
+
+	return setupArchive(cmd)
+}
+
+// setName sets the name of the project. If we are already in a directory named after the project, the project is
+// built in it rather than in a new subdirectory.
+func setName(name, wd string) {
+	viper.Set("name", name)
+
+	// The name may be set again by the wizard, which must not keep the location of the previous one
+	location := ""
+	if filepath.Base(wd) == name {
+		location = filepath.Dir(wd)
+	}
+
+	viper.Set("location", location)
+}
+
+// setupBuild sets the values of the flags added by buildFlags
+func setupBuild(cmd *cobra.Command) error {
 	if cmd.Flag("profile").Value.String() != "" {
 		viper.Set("profile", cmd.Flag("profile").Value.String())
 	}
+
+	flagVars, err := cmd.Flags().GetStringArray("var")
+	if err != nil {
+		return err
+	}
+
+	vars, err := parseVars(flagVars)
+	if err != nil {
+		return err
+	}
+
+	viper.Set("vars", vars)
+
+	for _, flag := range []string{"dry-run", "show-content", "diff", "keep-going"} {
+		value, err := cmd.Flags().GetBool(flag)
+		if err != nil {
+			return err
+		}
+
+		viper.Set(flag, value)
+	}
+
+	jobs, err := cmd.Flags().GetInt("jobs")
+	if err != nil {
+		return err
+	}
+
+	if jobs < 0 {
+		return fmt.Errorf("error : invalid value '%d' for --jobs, expected a positive number", jobs)
+	}
+
+	viper.Set("jobs", jobs)
+
+	if err := setupReport(cmd); err != nil {
+		return err
+	}
+
+	return setupConflicts(cmd)
+}
+
+// setupConflicts validates and sets the handling of files that already exist
+func setupConflicts(cmd *cobra.Command) error {
+	onConflict := cmd.Flag("on-conflict").Value.String()
+	if onConflict != "" {
+		if err := templates.CheckOnConflict(onConflict); err != nil {
+			return fmt.Errorf("error : %v", err)
+		}
+	}
+
+	style := cmd.Flag("conflict-style").Value.String()
+	switch style {
+	case templates.ConflictMarkers, templates.ConflictFile:
+	default:
+		return fmt.Errorf("error : invalid value '%s' for --conflict-style, expected markers or file", style)
+	}
+
+	viper.Set("on-conflict", onConflict)
+	viper.Set("conflict-style", style)
+
+	return nil
+}
+
+// setupReport validates and sets the format and destination of the build report
+func setupReport(cmd *cobra.Command) error {
+	format := cmd.Flag("report").Value.String()
+	file := cmd.Flag("report-file").Value.String()
+
+	if format == "" && file != "" {
+		format = reportJSON
+	}
+
+	if format != "" && format != reportJSON {
+		return fmt.Errorf("error : invalid value '%s' for --report, expected json", format)
+	}
+
+	viper.Set("report", format)
+	viper.Set("report-file", file)
+
+	return nil
+}
+
+// parseVars parses key=value pairs into template variables. As in a profile, true and false are booleans.
+func parseVars(pairs []string) (map[string]interface{}, error) {
+	vars := make(map[string]interface{}, len(pairs))
+
+	for _, pair := range pairs {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid variable '%s', expected key=value", pair)
+		}
+
+		switch kv[1] {
+		case "true":
+			vars[kv[0]] = true
+		case "false":
+			vars[kv[0]] = false
+		default:
+			vars[kv[0]] = kv[1]
+		}
+	}
+
+	return vars, nil
 }
 
 // checkVars verifies all necessary information is given to build a new project
@@ -73,23 +292,40 @@ func checkVars() error {
 	return nil
 }
 
-func newProject() {
+// loadProfile loads the profile given on the command line, or the default profile if none was given
+func loadProfile() (*config.Profile, error) {
 	// If profile is given
 	profileName := viper.GetString("profile")
 	if profileName == "" {
-		fmt.Println("Loading default profile")
 		// No profile was specified, we're therefore calling the default profile
 		profileName = viper.GetString(config.DefaultConfigProfileKeyName)
 		if profileName == "" {
-			fmt.Println("Error : no profile was specified, and no default profile was found.")
-			os.Exit(1)
+			return nil, errors.New("error : no profile was specified, and no default profile was found")
+		}
+	}
+
+	return config.LoadProfile(profileName)
+}
+
+func newProject(interactive bool) error {
+	out := output()
+
+	// The wizard also asks whether to overwrite files under the prompt policy
+	w := newWizard(os.Stdin, out)
+
+	if interactive {
+		if err := w.askProject(); err != nil {
+			return err
 		}
 	}
 
-	prof, err := config.LoadProfile(profileName)
+	if viper.GetString("profile") == "" {
+		fmt.Fprintln(out, "Loading default profile")
+	}
+
+	prof, err := loadProfile()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 
 	// Initiate and create project
@@ -100,14 +336,100 @@ func newProject() {
 		projectLocation = config.DefaultTargetProjectLocation
 	}
 
-	project := templates.NewProject(prof, projectName, projectLocation)
+	// Archives are built in memory
+	archive := viper.GetString("archive")
+	if archive != "" {
+		projectLocation = string(filepath.Separator)
+	}
+
+	project, err := templates.NewProject(prof, projectName, projectLocation, viper.GetStringMap("vars"))
+	if err != nil {
+		return err
+	}
+
+	if archive != "" {
+		project.FS = vfs.NewMemory()
+	}
+
+	if module := viper.GetString("module"); module != "" {
+		project.Module = module
+	}
+
+	setupProject(project, w)
+
+	if interactive {
+		proceed, err := w.askLayout(project)
+		if err != nil {
+			return err
+		}
+
+		if !proceed {
+			fmt.Fprintln(out, "Aborted.")
+			return nil
+		}
+	}
+
+	// Only show what would be done
+	if viper.GetBool("dry-run") {
+		return dryRun(project)
+	}
+
+	report, err := buildProject(project)
+
+	// With --keep-going, what could be built is archived nonetheless
+	if archive != "" && report != nil && report.Written {
+		if aerr := writeArchive(project, archive); aerr != nil {
+			return aerr
+		}
+	}
+
+	if err != nil {
+		return err
+	}
 
-	// Build project
-	if err := project.Build(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	if archive != "" {
+		fmt.Fprintf(out, "Project %s was successfully archived.\n", projectName)
+		return nil
 	}
 
-	fmt.Printf("Project %s was successfully created.\n", projectName)
-	os.Exit(0)
+	fmt.Fprintf(out, "Project %s was successfully created.\n", projectName)
+
+	return nil
+}
+
+// setupProject applies the handling of existing files and failures set on the command line to the project.
+// Overwriting files under the prompt policy is only asked in a terminal.
+func setupProject(project *templates.Project, w *wizard) {
+	project.OnConflict = viper.GetString("on-conflict")
+	project.ConflictStyle = viper.GetString("conflict-style")
+	project.KeepGoing = viper.GetBool("keep-going")
+	project.Jobs = viper.GetInt("jobs")
+	project.Out = output()
+
+	// Without a terminal to ask, files are skipped
+	if !isTerminal(os.Stdin) {
+		return
+	}
+
+	project.Confirm = func(question string) (bool, error) {
+		overwrite, err := w.confirm(question, false)
+		if err == io.EOF {
+			// The input is closed, e.g. redirected from /dev/null : there is no one to answer
+			return false, nil
+		}
+
+		return overwrite, err
+	}
+}
+
+// dryRun prints what building the project would do
+func dryRun(project *templates.Project) error {
+	plan, err := project.Plan()
+	if err != nil {
+		return err
+	}
+
+	plan.Print(os.Stdout, viper.GetBool("show-content"), viper.GetBool("diff"))
+
+	return nil
 }
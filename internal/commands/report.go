@@ -0,0 +1,68 @@
+// Package commands holds the different CLI commands
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/bytemare/goproject/internal/config"
+	"github.com/bytemare/goproject/internal/templates"
+
+	"github.com/spf13/viper"
+)
+
+// reportJSON is the json format of the build report
+const reportJSON = "json"
+
+// output returns where to print the progress of a command : the standard error if the report or the archive is
+// printed on the standard output, and the standard output otherwise
+func output() io.Writer {
+	if viper.GetString("report") != "" && viper.GetString("report-file") == "" || viper.GetString("archive") == "-" {
+		return os.Stderr
+	}
+
+	return os.Stdout
+}
+
+// buildProject builds the project and emits its report if one was asked for, even if the build failed
+func buildProject(project *templates.Project) (*templates.Report, error) {
+	report, err := project.Build()
+
+	if report != nil && viper.GetString("report") != "" {
+		if rerr := writeReport(report); rerr != nil {
+			if err != nil {
+				fmt.Fprintln(output(), rerr)
+				return report, err
+			}
+
+			return report, rerr
+		}
+	}
+
+	return report, err
+}
+
+// writeReport writes the report as json to the report file, or to the standard output
+func writeReport(report *templates.Report) error {
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error : could not encode report : %v", err)
+	}
+
+	content = append(content, '\n')
+
+	file := viper.GetString("report-file")
+	if file == "" {
+		_, err = os.Stdout.Write(content)
+		return err
+	}
+
+	if err := ioutil.WriteFile(file, content, config.FileMode); err != nil {
+		return fmt.Errorf("error : could not write report to '%s' : %v", file, err)
+	}
+
+	return nil
+}
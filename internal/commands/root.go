@@ -34,6 +34,9 @@ func Execute() {
 	rootCmd := getRootCmd()
 	rootCmd.AddCommand(newCmd())
 	rootCmd.AddCommand(profileCmd())
+	rootCmd.AddCommand(templateCmd())
+	rootCmd.AddCommand(updateCmd())
+	rootCmd.AddCommand(undoCmd())
 	rootCmd.AddCommand(versionCmd())
 	rootCmd.AddCommand(upgradeCmd())
 
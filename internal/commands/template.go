@@ -0,0 +1,180 @@
+// Package commands holds the different CLI commands
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bytemare/goproject/internal/config"
+	"github.com/bytemare/goproject/internal/templates"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// templateCmd represents the template command
+func templateCmd() *cobra.Command {
+	templateCmd := &cobra.Command{
+		Use:   "template",
+		Short: "Inspect the available templates",
+		Long: `The template command lets you inspect the templates that can be used in a profile's layout.
+It allows you to list them, show their raw content, and render them for a profile.`,
+		Args: cobra.MinimumNArgs(1),
+	}
+
+	templateCmd.PersistentFlags().StringP("profile", "p", "", "Specify the profile you want to use")
+
+	templateCmd.AddCommand(templateCmdList())
+	templateCmd.AddCommand(templateCmdShow())
+	templateCmd.AddCommand(templateCmdRender())
+
+	return templateCmd
+}
+
+func templateCmdList() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "list available templates",
+		Long: `list the built-in templates and the user templates of the profile, with the files they create.
+User templates are those of the templates directory, and those declared in the profile.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			templateList(loadTemplateProfile(cmd))
+		},
+	}
+}
+
+func templateCmdShow() *cobra.Command {
+	return &cobra.Command{
+		Use:     "show [template identifier]",
+		Short:   "show a raw template",
+		Long:    "show the raw content of a template, as registered for the profile",
+		Example: "show makefile",
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			templateShow(loadTemplateProfile(cmd), args[0])
+		},
+	}
+}
+
+func templateCmdRender() *cobra.Command {
+	renderCmd := &cobra.Command{
+		Use:   "render [template identifier]",
+		Short: "render a template",
+		Long: `render a template with the values of the profile, and print the result on the standard output.
+Nothing is written to disk.`,
+		Example: "render makefile -p myProfile --name myApp",
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			prof := loadTemplateProfile(cmd)
+
+			wd, err := os.Getwd()
+			if err != nil {
+				fmt.Printf("Unable to get working directory : %s\n", err)
+				os.Exit(1)
+			}
+
+			location := wd
+
+			name, _ := cmd.Flags().GetString("name")
+			if name == "" || name == filepath.Base(wd) {
+				name, location = filepath.Base(wd), filepath.Dir(wd)
+			}
+
+			flagVars, err := cmd.Flags().GetStringArray("var")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			vars, err := parseVars(flagVars)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			project, err := templates.NewProject(prof, name, location, vars)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			templateRender(project, args[0])
+		},
+	}
+
+	renderCmd.Flags().String("name", "", "Name of the project to render the template for (defaults to the current directory's name)")
+	renderCmd.Flags().StringArray("var", nil, "Set a template variable, as key=value (can be repeated)")
+
+	return renderCmd
+}
+
+// loadTemplateProfile loads the profile given on the command line, or the default one, and exits on failure
+func loadTemplateProfile(cmd *cobra.Command) *config.Profile {
+	if profile, _ := cmd.Flags().GetString("profile"); profile != "" {
+		viper.Set("profile", profile)
+	}
+
+	prof, err := loadProfile()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	return prof
+}
+
+func templateList(prof *config.Profile) {
+	fmt.Println("Available templates :")
+
+	for _, t := range templates.ListTemplates(prof) {
+		paths := make([]string, len(t.Files))
+		for i, f := range t.Files {
+			paths[i] = f.Path
+		}
+
+		fmt.Printf("\t- %s [%s] : %s\n", t.ID, t.Source, strings.Join(paths, ", "))
+	}
+
+	os.Exit(0)
+}
+
+func templateShow(prof *config.Profile, id string) {
+	t, err := templates.GetTemplate(prof, id)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	for _, f := range t.Files {
+		// Tell the files of a template tree apart
+		if len(t.Files) > 1 {
+			fmt.Printf("==> %s <==\n", f.Path)
+		}
+
+		fmt.Print(f.Template)
+	}
+
+	os.Exit(0)
+}
+
+func templateRender(project *templates.Project, id string) {
+	files, err := project.Render(id)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	for _, f := range files {
+		// Tell the files of a template tree apart
+		if len(files) > 1 {
+			fmt.Printf("==> %s <==\n", f.Path)
+		}
+
+		fmt.Print(f.Content)
+	}
+
+	os.Exit(0)
+}
@@ -0,0 +1,44 @@
+// Package commands holds the different CLI commands
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bytemare/goproject/internal/templates"
+	"github.com/bytemare/goproject/internal/vfs"
+
+	"github.com/spf13/cobra"
+)
+
+// undoCmd represents the undo command
+func undoCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "undo [project directory]",
+		Short: "Roll back the last generation of a project",
+		Long: `This command removes what the last 'goproject new' or 'goproject update' created in a project :
+directories, files, the go.mod file and the git repository.
+Files modified since they were generated, directories that are not empty, and git repositories with commits are kept.
+For example :
+
+./goproject undo myApp
+
+Will roll back the last generation of the project in the myApp directory, or in the current directory if none is given.
+`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			dir := "."
+			if len(args) == 1 {
+				dir = args[0]
+			}
+
+			if err := templates.Undo(vfs.OS{}, dir, os.Stdout); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			fmt.Println("Last generation was undone.")
+			os.Exit(0)
+		},
+	}
+}
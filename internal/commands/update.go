@@ -0,0 +1,121 @@
+// Package commands holds the different CLI commands
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bytemare/goproject/internal/config"
+	"github.com/bytemare/goproject/internal/templates"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// updateCmd represents the update command
+func updateCmd() *cobra.Command {
+	updateCmd := &cobra.Command{
+		Use:   "update [project directory]",
+		Short: "Update a project with the current templates and profile",
+		Long: `This command re-renders the files of a project generated by goproject, with the current templates and profile.
+It reads the project's generation manifest (.goproject.lock) to know the profile, the module and the variables
+given when the project was generated. The other variables are those of the current profile.
+For example :
+
+./goproject update
+
+Will update the project in the current directory. Files left as they were generated are updated, and new entries
+of the profile's layout are created. Files modified since they were generated are reported and skipped.
+
+./goproject update myApp --on-conflict merge
+
+Will update the project in the myApp directory, and merge the rendered files into the modified ones.
+
+./goproject update --dry-run --diff
+
+Will print what would be updated, and the differences, without touching the disk.
+`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := setupBuild(cmd); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			dir := "."
+			if len(args) == 1 {
+				dir = args[0]
+			}
+
+			if err := updateProject(dir); err != nil {
+				fmt.Fprintln(output(), err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	buildFlags(updateCmd)
+
+	return updateCmd
+}
+
+func updateProject(dir string) error {
+	out := output()
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("unable to get project directory : %v", err)
+	}
+
+	manifest, err := templates.ReadManifest(filepath.Join(dir, templates.ManifestName))
+	if err != nil {
+		return fmt.Errorf("error : no generation manifest in '%s', was it generated by goproject ? (%v)", dir, err)
+	}
+
+	// The profile the project was generated with, unless another one is given
+	profileName := viper.GetString("profile")
+	if profileName == "" {
+		profileName = manifest.Profile
+	}
+
+	prof, err := config.LoadProfile(profileName)
+	if err != nil {
+		return err
+	}
+
+	// The variables given when the project was generated replace those of the profile, and variables given on the
+	// command line replace both
+	vars := make(map[string]interface{}, len(manifest.Vars))
+	for k, v := range manifest.Vars {
+		vars[k] = v
+	}
+
+	for k, v := range viper.GetStringMap("vars") {
+		vars[k] = v
+	}
+
+	project, err := templates.NewProject(prof, manifest.Name, filepath.Dir(dir), vars)
+	if err != nil {
+		return err
+	}
+
+	project.Path = dir
+	project.Module = manifest.Module
+	project.Previous = manifest
+
+	setupProject(project, newWizard(os.Stdin, out))
+
+	// Only show what would be done
+	if viper.GetBool("dry-run") {
+		return dryRun(project)
+	}
+
+	if _, err := buildProject(project); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Project %s was successfully updated.\n", manifest.Name)
+
+	return nil
+}
@@ -0,0 +1,258 @@
+// Package commands holds the different CLI commands
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/bytemare/goproject/internal/config"
+	"github.com/bytemare/goproject/internal/templates"
+
+	"github.com/spf13/viper"
+)
+
+// wizard interactively walks the user through the creation of a new project
+type wizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newWizard(in io.Reader, out io.Writer) *wizard {
+	return &wizard{
+		in:  bufio.NewReader(in),
+		out: out,
+	}
+}
+
+// isTerminal returns whether the file is a terminal, rather than a pipe or a regular file
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// ask prompts the question and returns the answer, or the default value if the answer is empty
+func (w *wizard) ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(w.out, "%s [%s] : ", question, def)
+	} else {
+		fmt.Fprintf(w.out, "%s : ", question)
+	}
+
+	answer, err := w.in.ReadString('\n')
+	if err != nil && (err != io.EOF || answer == "") {
+		return "", err
+	}
+
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return def, nil
+	}
+
+	return answer, nil
+}
+
+// confirm prompts a yes/no question, until the answer is valid
+func (w *wizard) confirm(question string, def bool) (bool, error) {
+	choices := "y/N"
+	if def {
+		choices = "Y/n"
+	}
+
+	for {
+		answer, err := w.ask(fmt.Sprintf("%s (%s)", question, choices), "")
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		default:
+			fmt.Fprintln(w.out, "Please answer y or n.")
+		}
+	}
+}
+
+// askProject prompts for the project name and the profile to use
+func (w *wizard) askProject() error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	// The name and the profile given on the command line are the defaults
+	name, err := w.ask("Project name", viper.GetString("name"))
+	if err != nil {
+		return err
+	}
+
+	setName(name, wd)
+
+	profiles, err := config.ListProfiles()
+	if err != nil {
+		return err
+	}
+
+	if len(profiles) != 0 {
+		fmt.Fprintf(w.out, "Available profiles : %s\n", strings.Join(profiles, ", "))
+	}
+
+	def := viper.GetString("profile")
+	if def == "" {
+		def = viper.GetString(config.DefaultConfigProfileKeyName)
+	}
+
+	def = strings.TrimSuffix(def, ".toml")
+
+	profile, err := w.ask("Profile", def)
+	if err != nil {
+		return err
+	}
+
+	if profile != "" && !strings.HasSuffix(profile, ".toml") {
+		profile += ".toml"
+	}
+
+	viper.Set("profile", profile)
+
+	return nil
+}
+
+// askLayout prompts for the module path, the layout entries to keep, and the template variables the profile left
+// empty. It returns whether the user wants to proceed after reviewing the summary.
+func (w *wizard) askLayout(project *templates.Project) (bool, error) {
+	module, err := w.ask("Module path", project.Module)
+	if err != nil {
+		return false, err
+	}
+
+	project.Module = module
+
+	if err := w.toggleLayout(project); err != nil {
+		return false, err
+	}
+
+	if err := w.askVars(project.Vars); err != nil {
+		return false, err
+	}
+
+	w.summary(project)
+
+	return w.confirm("Proceed", true)
+}
+
+// toggle asks whether to keep each of the entries, and returns their indexes
+func (w *wizard) toggle(question string, entries []fmt.Stringer) ([]int, error) {
+	kept := make([]int, 0, len(entries))
+
+	for i, e := range entries {
+		keep, err := w.confirm(fmt.Sprintf("%s %s ?", question, e), true)
+		if err != nil {
+			return nil, err
+		}
+
+		if keep {
+			kept = append(kept, i)
+		}
+	}
+
+	return kept, nil
+}
+
+// toggleLayout asks whether to keep each directory and file of the project's layout
+func (w *wizard) toggleLayout(project *templates.Project) error {
+	dirs := project.Layout.Directories
+	entries := make([]fmt.Stringer, len(dirs))
+
+	for i, d := range dirs {
+		entries[i] = d
+	}
+
+	kept, err := w.toggle("Create directory", entries)
+	if err != nil {
+		return err
+	}
+
+	project.Layout.Directories = make([]templates.DirectoryEntry, 0, len(kept))
+	for _, i := range kept {
+		project.Layout.Directories = append(project.Layout.Directories, dirs[i])
+	}
+
+	files := project.Layout.Files
+	entries = make([]fmt.Stringer, len(files))
+
+	for i, f := range files {
+		entries[i] = f
+	}
+
+	if kept, err = w.toggle("Create file", entries); err != nil {
+		return err
+	}
+
+	project.Layout.Files = make([]templates.FileEntry, 0, len(kept))
+	for _, i := range kept {
+		project.Layout.Files = append(project.Layout.Files, files[i])
+	}
+
+	return nil
+}
+
+// askVars prompts for a value for each empty variable
+func (w *wizard) askVars(vars map[string]interface{}) error {
+	keys := make([]string, 0, len(vars))
+
+	for k, v := range vars {
+		if v == nil || v == "" {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		value, err := w.ask(fmt.Sprintf("Value for variable '%s'", k), "")
+		if err != nil {
+			return err
+		}
+
+		vars[k] = value
+	}
+
+	return nil
+}
+
+// summary prints what is about to be generated
+func (w *wizard) summary(project *templates.Project) {
+	fmt.Fprintln(w.out, "\nSummary :")
+	fmt.Fprintf(w.out, "\tName        : %s\n", project.Name)
+	fmt.Fprintf(w.out, "\tLocation    : %s\n", project.Path)
+	fmt.Fprintf(w.out, "\tModule      : %s\n", project.Module)
+	fmt.Fprintf(w.out, "\tProfile     : %s\n", viper.GetString("profile"))
+	fmt.Fprintf(w.out, "\tDirectories : %v\n", project.Layout.Directories)
+	fmt.Fprintf(w.out, "\tFiles       : %v\n", project.Layout.Files)
+
+	keys := make([]string, 0, len(project.Vars))
+	for k := range project.Vars {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintf(w.out, "\tVariable    : %s = %v\n", k, project.Vars[k])
+	}
+
+	fmt.Fprintln(w.out)
+}
@@ -29,6 +29,8 @@ func initConfig(location string) error {
 	viper.SetConfigPermissions(fm)
 	viper.Set("title", confTitle)
 	viper.Set(profileDirConfigKeyName, profileDirName)
+	viper.Set(templateDirConfigKeyName, templateDirName)
+	viper.Set(partialDirConfigKeyName, partialDirName)
 	viper.Set(DefaultConfigProfileKeyName, defaultProfileName)
 	viper.Set(DefaultAutoUpdateKeyName, true)
 
@@ -37,7 +39,16 @@ func initConfig(location string) error {
 	}
 
 	// Create profile directory and default profile
-	return initProfiles(location)
+	if err := initProfiles(location); err != nil {
+		return err
+	}
+
+	// Create the user template and partial directories
+	if err := initTemplates(location); err != nil {
+		return err
+	}
+
+	return initPartials(location)
 }
 
 func loadConfig() error {
@@ -69,11 +80,49 @@ func GetProfileDirName() (string, error) {
 		return "", errors.Wrap(err, "Could not get profile directory")
 	}
 
-	prof := fmt.Sprintf("%v", viper.Get(profileDirConfigKeyName))
+	// Without a configuration, e.g. when goproject is used as a library, the entry is not set
+	prof := viper.GetString(profileDirConfigKeyName)
+	if prof == "" {
+		prof = profileDirName
+	}
 
 	return path.Join(dir, prof), nil
 }
 
+// GetTemplateDirName returns the path to the goproject user template directory
+func GetTemplateDirName() (string, error) {
+	// Get configuration directory
+	dir, err := configDir()
+	if err != nil {
+		return "", errors.Wrap(err, "Could not get template directory")
+	}
+
+	// Configurations created before user templates existed don't have the entry
+	tmpl := viper.GetString(templateDirConfigKeyName)
+	if tmpl == "" {
+		tmpl = templateDirName
+	}
+
+	return path.Join(dir, tmpl), nil
+}
+
+// GetPartialDirName returns the path to the goproject user partial directory
+func GetPartialDirName() (string, error) {
+	// Get configuration directory
+	dir, err := configDir()
+	if err != nil {
+		return "", errors.Wrap(err, "Could not get partial directory")
+	}
+
+	// Configurations created before user partials existed don't have the entry
+	partial := viper.GetString(partialDirConfigKeyName)
+	if partial == "" {
+		partial = partialDirName
+	}
+
+	return path.Join(dir, partial), nil
+}
+
 /*
 func GetDefaultProfile() string {
 	return fmt.Sprintf("%v", viper.Get(DefaultProfileConfigKeyName))
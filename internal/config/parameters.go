@@ -4,12 +4,17 @@ package config
 import "os"
 
 const (
-	confDirName             string = "goproject"
-	confFileName            string = "conf.toml"
-	confType                string = "toml"
-	confTitle               string = "GoProject Configuration File"
-	profileDirConfigKeyName string = "profiledir"
-	profileDirName          string = "profiles"
+	confDirName              string = "goproject"
+	confFileName             string = "conf.toml"
+	confType                 string = "toml"
+	confTitle                string = "GoProject Configuration File"
+	profileDirConfigKeyName  string = "profiledir"
+	profileDirName           string = "profiles"
+	templateDirConfigKeyName string = "templatedir"
+	templateDirName          string = "templates"
+	partialDirConfigKeyName  string = "partialdir"
+	partialDirName           string = "partials"
+	partialExtension         string = "tmpl"
 )
 
 const (
@@ -0,0 +1,69 @@
+// Package config groups the configuration and profile management mechanism
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// initPartials creates the user partial directory if it does not exist
+func initPartials(location string) error {
+	return os.MkdirAll(path.Join(location, partialDirName), DirMode)
+}
+
+// LoadUserPartials returns the partials found in the user partial directory, indexed by their name, which is the
+// file name without its extension. A missing partial directory is not an error.
+func LoadUserPartials() (map[string]string, error) {
+	dir, err := GetPartialDirName()
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not load user partials")
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+
+		return nil, errors.Wrap(err, "Could not load user partials")
+	}
+
+	res := make(map[string]string, len(files))
+
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), "."+partialExtension) {
+			continue
+		}
+
+		content, err := ioutil.ReadFile(path.Join(dir, f.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Could not load partial '%s'", f.Name())
+		}
+
+		// Editors usually end files with a new line, which would break partials used inline
+		res[strings.TrimSuffix(f.Name(), "."+partialExtension)] = strings.TrimSuffix(string(content), "\n")
+	}
+
+	return res, nil
+}
+
+// loadPartials merges the partials of the user partial directory with those declared in the profile itself,
+// the latter taking precedence
+func (p *Profile) loadPartials() error {
+	partials, err := LoadUserPartials()
+	if err != nil {
+		return err
+	}
+
+	for name, partial := range p.Partials {
+		partials[name] = partial
+	}
+
+	p.Partials = partials
+
+	return nil
+}
@@ -2,6 +2,7 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -24,8 +25,16 @@ var _ = map[string]string{
 
 // Profile associates an Author to a configuration file, describing a desired project layout
 type Profile struct {
-	Author *Author
-	Conf   *viper.Viper
+	// Name is the name of the profile file
+	Name      string `mapstructure:"-"`
+	Author    *Author
+	Git       *Git
+	Travis    *Travis
+	Sonar     *Sonar
+	Docker    *Docker
+	Templates map[string]*Template
+	Partials  map[string]string
+	Conf      *viper.Viper
 }
 
 // Author represents the developer and holds their contact information
@@ -34,6 +43,36 @@ type Author struct {
 	Contact string
 }
 
+// Git holds the git identity of the developer, the base URL of their repositories, and how new repositories are
+// initialised
+type Git struct {
+	User string
+	Mail string
+	URL  string
+
+	// Branch is the name of the initial branch, git's default if empty
+	Branch string
+
+	// Commit tells to commit the generated files, with Message or "Initial commit" if empty
+	Commit  bool
+	Message string
+}
+
+// Travis holds the developer's Travis CI settings
+type Travis struct {
+	Profile string
+}
+
+// Sonar holds the developer's SonarCloud settings
+type Sonar struct {
+	Org string
+}
+
+// Docker holds the settings for the Docker images of the project
+type Docker struct {
+	Maintainer string
+}
+
 // initProfiles creates the profile directory if it does not exist
 func initProfiles(location string) error {
 	profileDir := path.Join(location, profileDirName)
@@ -64,12 +103,53 @@ func LoadProfile(name string) (*Profile, error) {
 		return nil, errors.Wrapf(err, "File for profile '%s' exists but could not be loaded", name)
 	}
 
+	p, err := decodeProfile(name, fileProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.loadTemplates(); err != nil {
+		return nil, errors.Wrapf(err, "Could not load templates for profile '%s'", name)
+	}
+
+	if err := p.loadPartials(); err != nil {
+		return nil, errors.Wrapf(err, "Could not load partials for profile '%s'", name)
+	}
+
+	return p, nil
+}
+
+// ParseProfile parses the toml content of a profile. Unlike LoadProfile, it does not read anything from disk : the
+// templates and partials of the user directories are not added to those of the profile.
+func ParseProfile(name string, content []byte) (*Profile, error) {
+	v := viper.New()
+	v.SetConfigType(confType)
+
+	if err := v.ReadConfig(bytes.NewReader(content)); err != nil {
+		return nil, errors.Wrapf(err, "Could not parse profile '%s'", name)
+	}
+
+	p, err := decodeProfile(name, v)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.addTemplates(make(map[string]*Template)); err != nil {
+		return nil, errors.Wrapf(err, "Could not load templates for profile '%s'", name)
+	}
+
+	return p, nil
+}
+
+// decodeProfile decodes the profile read by the viper instance
+func decodeProfile(name string, v *viper.Viper) (*Profile, error) {
 	var p Profile
-	if err := fileProfile.Unmarshal(&p); err != nil {
+	if err := v.Unmarshal(&p); err != nil {
 		return nil, errors.Wrapf(err, "Could not load profile '%s'", name)
 	}
 
-	p.Conf = fileProfile
+	p.Name = name
+	p.Conf = v
 
 	return &p, nil
 }
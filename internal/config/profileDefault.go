@@ -27,6 +27,9 @@ const (
 	user = "bytemare"
 	mail = "dev@bytema.re"
 	URL  = "github.com/bytemare"
+	branch = "main"
+	commit = false
+	message = "Initial commit"
 
 	[travis]
 	profile = "https://travis-ci.com/bytemare"
@@ -61,6 +64,9 @@ const (
 	user = ""
 	mail = ""
 	URL  = ""
+	branch = ""
+	commit = false
+	message = ""
 
 	[travis]
 	profile = ""
@@ -70,5 +76,9 @@ const (
 
 	[docker]
 	maintainer = ""
+
+	[vars]
+	# Custom variables, available to all templates as .Vars, e.g. {{.Vars.license}}
+	# license = "MIT"
 `
 )
@@ -0,0 +1,224 @@
+// Package config groups the configuration and profile management mechanism
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/spf13/viper"
+)
+
+// Template is a user-defined file template. It either overrides a built-in template registered under the same
+// identifier, or adds a new identifier that can be listed in a profile's layout.
+// Next to a single file, a template can hold a whole directory tree of files.
+type Template struct {
+	Identifier string
+	Filename   string
+	Directory  string
+	Template   string
+	Files      []*TemplateFile
+}
+
+// TemplateFile is a file of a template tree. Both its path, relative to the project root, and its content are templates
+type TemplateFile struct {
+	Path     string
+	Template string
+}
+
+// initTemplates creates the user template directory if it does not exist
+func initTemplates(location string) error {
+	return os.MkdirAll(path.Join(location, templateDirName), DirMode)
+}
+
+// validate verifies the template holds enough information to be rendered, and sets defaults
+func (t *Template) validate() error {
+	if t.Identifier == "" {
+		return errors.New("template has no identifier")
+	}
+
+	if t.Filename == "" && len(t.Files) == 0 {
+		return errors.Errorf("template '%s' has neither a filename nor files", t.Identifier)
+	}
+
+	for _, f := range t.Files {
+		if f == nil || f.Path == "" {
+			return errors.Errorf("template '%s' has a file without path", t.Identifier)
+		}
+	}
+
+	if t.Directory == "" {
+		t.Directory = "."
+	}
+
+	return nil
+}
+
+// loadTemplateFile loads a single user template from a toml file, the identifier being the file name
+func loadTemplateFile(filePath string) (*Template, error) {
+	identifier := strings.TrimSuffix(path.Base(filePath), "."+confType)
+
+	fileTemplate := viper.New()
+	fileTemplate.SetConfigFile(filePath)
+	fileTemplate.SetConfigType(confType)
+
+	if err := fileTemplate.ReadInConfig(); err != nil {
+		return nil, errors.Wrapf(err, "could not read template '%s'", identifier)
+	}
+
+	var t Template
+	if err := fileTemplate.Unmarshal(&t); err != nil {
+		return nil, errors.Wrapf(err, "could not load template '%s'", identifier)
+	}
+
+	t.Identifier = identifier
+
+	return &t, nil
+}
+
+// loadTemplateTree loads all files below the given directory as the files of a template tree
+func loadTemplateTree(dir string) ([]*TemplateFile, error) {
+	var files []*TemplateFile
+
+	err := filepath.Walk(dir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(filePath) //nolint:gosec // the template directory is chosen by the user
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, &TemplateFile{
+			Path:     filepath.ToSlash(rel),
+			Template: string(content),
+		})
+
+		return nil
+	})
+
+	return files, err
+}
+
+// LoadUserTemplates returns the templates found in the user template directory, indexed by their identifier.
+// A missing template directory is not an error, as it simply means no user templates were registered.
+func LoadUserTemplates() (map[string]*Template, error) {
+	dir, err := GetTemplateDirName()
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not load user templates")
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]*Template{}, nil
+		}
+
+		return nil, errors.Wrap(err, "Could not load user templates")
+	}
+
+	res := make(map[string]*Template, len(files))
+
+	// Hidden files and directories, such as .git, are not templates
+	visible := files[:0]
+	for _, f := range files {
+		if !strings.HasPrefix(f.Name(), ".") {
+			visible = append(visible, f)
+		}
+	}
+
+	files = visible
+
+	// Single file templates are toml files
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), "."+confType) {
+			continue
+		}
+
+		t, err := loadTemplateFile(path.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		res[t.Identifier] = t
+	}
+
+	// Template trees are directories, whose files are added to the toml template of the same name if there's one
+	for _, f := range files {
+		if !f.IsDir() {
+			continue
+		}
+
+		tree, err := loadTemplateTree(path.Join(dir, f.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not load template tree '%s'", f.Name())
+		}
+
+		// An empty directory is not a template tree
+		if len(tree) == 0 {
+			continue
+		}
+
+		t, ok := res[f.Name()]
+		if !ok {
+			t = &Template{Identifier: f.Name()}
+			res[f.Name()] = t
+		}
+
+		t.Files = append(t.Files, tree...)
+	}
+
+	for _, t := range res {
+		if err := t.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// loadTemplates merges the templates of the user template directory with those declared in the profile itself,
+// the latter taking precedence
+func (p *Profile) loadTemplates() error {
+	templates, err := LoadUserTemplates()
+	if err != nil {
+		return err
+	}
+
+	return p.addTemplates(templates)
+}
+
+// addTemplates validates the templates declared in the profile, and adds them to the given ones, replacing those of
+// the same identifier
+func (p *Profile) addTemplates(templates map[string]*Template) error {
+	for id, t := range p.Templates {
+		if t == nil {
+			t = &Template{}
+		}
+
+		t.Identifier = id
+		if err := t.validate(); err != nil {
+			return errors.Wrap(err, "invalid template in profile")
+		}
+
+		templates[id] = t
+	}
+
+	p.Templates = templates
+
+	return nil
+}
@@ -0,0 +1,272 @@
+// Package diff computes line based differences between texts
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op is the kind of an edit
+type Op int
+
+const (
+	// Equal means the line is in both texts
+	Equal Op = iota
+	// Delete means the line is only in the first text
+	Delete
+	// Insert means the line is only in the second text
+	Insert
+)
+
+// Edit is a single line edit transforming a text into another
+type Edit struct {
+	Op   Op
+	Line string
+}
+
+// Lines splits a text into lines, each keeping its new line character, so that a missing final new line
+// is a difference
+func Lines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// Compute returns the shortest sequence of line edits transforming a into b, using the linear space variant of
+// Myers' algorithm : memory grows with the length of the texts, not with the number of differences.
+func Compute(a, b []string) []Edit {
+	return appendEdits(make([]Edit, 0, len(a)+len(b)), a, b)
+}
+
+// appendEdits appends the edits transforming a into b. Common prefixes and suffixes are equal lines, and the rest is
+// divided at the middle snake of the shortest edit path, whose halves are computed recursively.
+func appendEdits(edits []Edit, a, b []string) []Edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits = appendLines(edits, Equal, a[:prefix])
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	switch {
+	case len(ma) == 0:
+		edits = appendLines(edits, Insert, mb)
+	case len(mb) == 0:
+		edits = appendLines(edits, Delete, ma)
+	default:
+		// Both differ at their first and last lines : at least two edits, each half having fewer
+		x, y, u, v := middleSnake(ma, mb)
+		edits = appendEdits(edits, ma[:x], mb[:y])
+		edits = appendLines(edits, Equal, ma[x:u])
+		edits = appendEdits(edits, ma[u:], mb[v:])
+	}
+
+	return appendLines(edits, Equal, a[len(a)-suffix:])
+}
+
+func appendLines(edits []Edit, op Op, lines []string) []Edit {
+	for _, l := range lines {
+		edits = append(edits, Edit{Op: op, Line: l})
+	}
+
+	return edits
+}
+
+// middleSnake returns the start (x, y) and the end (u, v) of the snake in the middle of a shortest edit path from a
+// to b, searching forward from their start and backward from their end at the same time until both searches meet.
+func middleSnake(a, b []string) (int, int, int, int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	limit := (n + m + 1) / 2
+	offset := limit + 1
+
+	// forward holds, for each diagonal k, the furthest x reached from the start. backward holds, for each diagonal of
+	// the reversed texts, the furthest x reached from the end, counted from the end.
+	forward := make([]int, 2*limit+3)
+	backward := make([]int, 2*limit+3)
+
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+
+			x0, y0 := x, x-k
+			y := y0
+
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			forward[offset+k] = x
+
+			// The backward search is one step behind on the same diagonal
+			if r := delta - k; odd && r >= -(d-1) && r <= d-1 && x+backward[offset+r] >= n {
+				return x0, y0, x, y
+			}
+		}
+
+		for r := -d; r <= d; r += 2 {
+			var x int
+			if r == -d || (r != d && backward[offset+r-1] < backward[offset+r+1]) {
+				x = backward[offset+r+1]
+			} else {
+				x = backward[offset+r-1] + 1
+			}
+
+			x0, y0 := x, x-r
+			y := y0
+
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+
+			backward[offset+r] = x
+
+			if k := delta - r; !odd && k >= -d && k <= d && x+forward[offset+k] >= n {
+				return n - x, m - y, n - x0, m - y0
+			}
+		}
+	}
+
+	// Unreachable : the searches meet after at most half the length of both texts
+	return n, m, n, m
+}
+
+// Unified returns the differences between a and b in the unified format, with the given number of context lines.
+// It returns an empty string if both texts are identical.
+func Unified(fromName, toName, a, b string, context int) string {
+	edits := Compute(Lines(a), Lines(b))
+
+	var sb strings.Builder
+
+	for _, h := range hunks(edits, context) {
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+		}
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(h.fromLine, h.fromCount), hunkRange(h.toLine, h.toCount))
+
+		for _, e := range h.edits {
+			switch e.Op {
+			case Equal:
+				sb.WriteString(" ")
+			case Delete:
+				sb.WriteString("-")
+			case Insert:
+				sb.WriteString("+")
+			}
+
+			sb.WriteString(e.Line)
+
+			if !strings.HasSuffix(e.Line, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// hunk is a group of edits, with surrounding context
+type hunk struct {
+	fromLine, fromCount int
+	toLine, toCount     int
+	edits               []Edit
+}
+
+// hunks groups the edits into hunks, merging changes separated by at most twice the context
+func hunks(edits []Edit, context int) []*hunk {
+	changes := make([]int, 0, len(edits))
+
+	for i, e := range edits {
+		if e.Op != Equal {
+			changes = append(changes, i)
+		}
+	}
+
+	var res []*hunk
+
+	for i := 0; i < len(changes); {
+		j := i
+		for j+1 < len(changes) && changes[j+1]-changes[j] <= 2*context+1 {
+			j++
+		}
+
+		start := maxInt(0, changes[i]-context)
+		end := minInt(len(edits), changes[j]+context+1)
+		h := &hunk{edits: edits[start:end], fromLine: 1, toLine: 1}
+
+		for _, e := range edits[:start] {
+			if e.Op != Insert {
+				h.fromLine++
+			}
+
+			if e.Op != Delete {
+				h.toLine++
+			}
+		}
+
+		for _, e := range h.edits {
+			if e.Op != Insert {
+				h.fromCount++
+			}
+
+			if e.Op != Delete {
+				h.toCount++
+			}
+		}
+
+		res = append(res, h)
+		i = j + 1
+	}
+
+	return res
+}
+
+// hunkRange formats the range of a hunk. An empty range refers to the line before it.
+func hunkRange(line, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", line-1)
+	case 1:
+		return fmt.Sprintf("%d", line)
+	default:
+		return fmt.Sprintf("%d,%d", line, count)
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
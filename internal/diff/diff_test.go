@@ -0,0 +1,231 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+// apply returns both texts an edit script transforms
+func apply(edits []Edit) (a, b string) {
+	var sa, sb strings.Builder
+
+	for _, e := range edits {
+		if e.Op != Insert {
+			sa.WriteString(e.Line)
+		}
+
+		if e.Op != Delete {
+			sb.WriteString(e.Line)
+		}
+	}
+
+	return sa.String(), sb.String()
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		text  string
+		lines []string
+	}{
+		{"", nil},
+		{"a", []string{"a"}},
+		{"a\n", []string{"a\n"}},
+		{"a\nb", []string{"a\n", "b"}},
+		{"a\n\nb\n", []string{"a\n", "\n", "b\n"}},
+	}
+
+	for _, tt := range tests {
+		if got := Lines(tt.text); !equal(got, tt.lines) {
+			t.Errorf("Lines(%q) = %q, want %q", tt.text, got, tt.lines)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		changes int
+	}{
+		{"identical", "a\nb\nc\n", "a\nb\nc\n", 0},
+		{"both empty", "", "", 0},
+		{"from empty", "", "a\nb\n", 2},
+		{"to empty", "a\nb\n", "", 2},
+		{"insert", "a\nc\n", "a\nb\nc\n", 1},
+		{"delete", "a\nb\nc\n", "a\nc\n", 1},
+		{"replace", "a\nb\nc\n", "a\nx\nc\n", 2},
+		{"missing final new line", "a\nb\n", "a\nb", 2},
+		{"move", "a\nb\nc\nd\n", "b\nc\nd\na\n", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edits := Compute(Lines(tt.a), Lines(tt.b))
+
+			if a, b := apply(edits); a != tt.a || b != tt.b {
+				t.Fatalf("edits give %q and %q, want %q and %q", a, b, tt.a, tt.b)
+			}
+
+			changes := 0
+			for _, e := range edits {
+				if e.Op != Equal {
+					changes++
+				}
+			}
+
+			if changes != tt.changes {
+				t.Errorf("got %d changes, want %d", changes, tt.changes)
+			}
+		})
+	}
+}
+
+func TestUnified(t *testing.T) {
+	if got := Unified("a", "b", "x\ny\n", "x\ny\n", 3); got != "" {
+		t.Errorf("identical texts have differences : %q", got)
+	}
+
+	want := "--- a/f\n+++ b/f\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n"
+	if got := Unified("a/f", "b/f", "a\nb\nc\n", "a\nx\nc\n", 3); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	want = "--- a/f\n+++ b/f\n@@ -1 +1 @@\n-a\n+a\n\\ No newline at end of file\n"
+	if got := Unified("a/f", "b/f", "a\n", "a", 3); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name                     string
+		base, current, generated string
+		merged                   string
+		conflicts                int
+	}{
+		{
+			name:      "unchanged",
+			base:      "a\nb\n",
+			current:   "a\nb\n",
+			generated: "a\nb\n",
+			merged:    "a\nb\n",
+		},
+		{
+			name:      "current changed",
+			base:      "a\nb\nc\n",
+			current:   "a\nB\nc\n",
+			generated: "a\nb\nc\n",
+			merged:    "a\nB\nc\n",
+		},
+		{
+			name:      "generated changed",
+			base:      "a\nb\nc\n",
+			current:   "a\nb\nc\n",
+			generated: "a\nb\nc\nd\n",
+			merged:    "a\nb\nc\nd\n",
+		},
+		{
+			name:      "both changed apart",
+			base:      "a\nb\nc\nd\ne\n",
+			current:   "A\nb\nc\nd\ne\n",
+			generated: "a\nb\nc\nd\nE\n",
+			merged:    "A\nb\nc\nd\nE\n",
+		},
+		{
+			name:      "same change on both sides",
+			base:      "a\nb\nc\n",
+			current:   "a\nx\nc\n",
+			generated: "a\nx\nc\n",
+			merged:    "a\nx\nc\n",
+		},
+		{
+			name:      "line removed on one side",
+			base:      "a\nb\nc\n",
+			current:   "a\nc\n",
+			generated: "a\nb\nc\n",
+			merged:    "a\nc\n",
+		},
+		{
+			name:      "conflict",
+			base:      "a\nb\nc\n",
+			current:   "a\nx\nc\n",
+			generated: "a\ny\nc\n",
+			merged:    "a\n<<<<<<< current\nx\n=======\ny\n>>>>>>> generated\nc\n",
+			conflicts: 1,
+		},
+		{
+			name:      "conflict without final new line",
+			base:      "a\nb",
+			current:   "a\nx",
+			generated: "a\ny",
+			merged:    "a\n<<<<<<< current\nx\n=======\ny\n>>>>>>> generated\n",
+			conflicts: 1,
+		},
+		{
+			name:      "two conflicts",
+			base:      "a\nb\nc\nd\ne\n",
+			current:   "x\nb\nc\nd\nx\n",
+			generated: "y\nb\nc\nd\ny\n",
+			merged: "<<<<<<< current\nx\n=======\ny\n>>>>>>> generated\nb\nc\nd\n" +
+				"<<<<<<< current\nx\n=======\ny\n>>>>>>> generated\n",
+			conflicts: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, conflicts := Merge(tt.base, tt.current, tt.generated)
+
+			if merged != tt.merged {
+				t.Errorf("got\n%s\nwant\n%s", merged, tt.merged)
+			}
+
+			if conflicts != tt.conflicts {
+				t.Errorf("got %d conflicts, want %d", conflicts, tt.conflicts)
+			}
+		})
+	}
+}
+
+func TestMergeWithoutBase(t *testing.T) {
+	tests := []struct {
+		name               string
+		current, generated string
+		merged             string
+		conflicts          int
+	}{
+		{
+			name:      "lines added on both sides",
+			current:   "a\nb\nmine\n",
+			generated: "theirs\na\nb\n",
+			merged:    "theirs\na\nb\nmine\n",
+		},
+		{
+			name:      "generated holds everything",
+			current:   "a\nb\n",
+			generated: "a\nb\n",
+			merged:    "a\nb\n",
+		},
+		{
+			name:      "differing lines at the same place",
+			current:   "a\nx\nc\n",
+			generated: "a\ny\nc\n",
+			merged:    "a\n<<<<<<< current\nx\n=======\ny\n>>>>>>> generated\nc\n",
+			conflicts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, conflicts := MergeWithoutBase(tt.current, tt.generated)
+
+			if merged != tt.merged {
+				t.Errorf("got\n%s\nwant\n%s", merged, tt.merged)
+			}
+
+			if conflicts != tt.conflicts {
+				t.Errorf("got %d conflicts, want %d", conflicts, tt.conflicts)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// Package diff computes line based differences between texts
+package diff
+
+import "strings"
+
+// Conflict markers, as used by git
+const (
+	markerCurrent   = "<<<<<<< current\n"
+	markerSeparator = "=======\n"
+	markerGenerated = ">>>>>>> generated\n"
+)
+
+// Merge is a three-way merge of the current and generated versions of a text, given the base they both derive from.
+// Changes of only one side are applied, and changes of both sides to the same lines are conflicts, which are
+// delimited with git-style conflict markers in the result. It returns the merged text and the number of conflicts.
+func Merge(base, current, generated string) (merged string, conflicts int) {
+	b, c, g := Lines(base), Lines(current), Lines(generated)
+
+	// For each line of the base, its position in both other versions, if it was kept
+	inCurrent := matches(b, c)
+	inGenerated := matches(b, g)
+
+	var sb strings.Builder
+
+	i, j, k := 0, 0, 0
+
+	for {
+		// Find the next line of the base kept in both versions
+		s := i
+		for s < len(b) && (inCurrent[s] < 0 || inGenerated[s] < 0) {
+			s++
+		}
+
+		if s == len(b) {
+			conflicts += mergeChunk(&sb, b[i:], c[j:], g[k:])
+			break
+		}
+
+		conflicts += mergeChunk(&sb, b[i:s], c[j:inCurrent[s]], g[k:inGenerated[s]])
+
+		sb.WriteString(b[s])
+
+		i, j, k = s+1, inCurrent[s]+1, inGenerated[s]+1
+	}
+
+	return sb.String(), conflicts
+}
+
+// MergeWithoutBase merges the current and generated versions of a text when their base is unknown.
+// The lines common to both versions are taken as the base, so that lines added on only one side are kept,
+// and differing lines at the same place are conflicts.
+func MergeWithoutBase(current, generated string) (merged string, conflicts int) {
+	var base strings.Builder
+
+	for _, e := range Compute(Lines(current), Lines(generated)) {
+		if e.Op == Equal {
+			base.WriteString(e.Line)
+		}
+	}
+
+	return Merge(base.String(), current, generated)
+}
+
+// matches returns, for each line of a, the index of the matching line in b, or -1 if it was deleted
+func matches(a, b []string) []int {
+	res := make([]int, len(a))
+
+	i, j := 0, 0
+
+	for _, e := range Compute(a, b) {
+		switch e.Op {
+		case Equal:
+			res[i] = j
+			i++
+			j++
+		case Delete:
+			res[i] = -1
+			i++
+		case Insert:
+			j++
+		}
+	}
+
+	return res
+}
+
+// mergeChunk writes the merge of a chunk where the versions differ, and returns 1 if it is a conflict
+func mergeChunk(sb *strings.Builder, base, current, generated []string) int {
+	switch {
+	case equal(current, generated), equal(base, generated):
+		writeLines(sb, current)
+	case equal(base, current):
+		writeLines(sb, generated)
+	default:
+		sb.WriteString(markerCurrent)
+		writeLines(sb, current)
+		terminate(sb)
+		sb.WriteString(markerSeparator)
+		writeLines(sb, generated)
+		terminate(sb)
+		sb.WriteString(markerGenerated)
+
+		return 1
+	}
+
+	return 0
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func writeLines(sb *strings.Builder, lines []string) {
+	for _, l := range lines {
+		sb.WriteString(l)
+	}
+}
+
+// terminate ends the text with a new line, so that a conflict marker can follow
+func terminate(sb *strings.Builder) {
+	if sb.Len() != 0 && !strings.HasSuffix(sb.String(), "\n") {
+		sb.WriteString("\n")
+	}
+}
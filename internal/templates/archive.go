@@ -0,0 +1,22 @@
+// Package templates holds the template and project building functions
+package templates
+
+import (
+	"io"
+	"path"
+	"path/filepath"
+
+	"github.com/bytemare/goproject/internal/vfs"
+)
+
+// Archive writes the project, as built on its file system, into an archive of the given format, under a directory
+// named after the project. goproject's local state, the journal and the bases of merges, is left out, as there is
+// nothing to undo or merge in an archive.
+func (p *Project) Archive(w io.Writer, format string) error {
+	root, err := filepath.Abs(p.Path)
+	if err != nil {
+		return err
+	}
+
+	return vfs.WriteArchive(w, format, p.fs(), root, filepath.Base(root), path.Dir(journalName))
+}
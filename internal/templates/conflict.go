@@ -0,0 +1,174 @@
+// Package templates holds the template and project building functions
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bytemare/goproject/internal/diff"
+
+	"github.com/pkg/errors"
+)
+
+// Policies for rendered files that already exist in the project
+const (
+	OnConflictSkip      = "skip"
+	OnConflictOverwrite = "overwrite"
+	OnConflictBackup    = "backup"
+	OnConflictPrompt    = "prompt"
+	OnConflictFail      = "fail"
+	OnConflictMerge     = "merge"
+)
+
+// backupFormat is the format of the timestamp suffixed to the files backed up before being overwritten
+const backupFormat = "20060102-150405"
+
+// OnConflictPolicies lists the valid policies for files that already exist
+func OnConflictPolicies() []string {
+	return []string{
+		OnConflictSkip,
+		OnConflictOverwrite,
+		OnConflictBackup,
+		OnConflictPrompt,
+		OnConflictFail,
+		OnConflictMerge,
+	}
+}
+
+// CheckOnConflict returns an error if the policy is not a valid policy for files that already exist
+func CheckOnConflict(policy string) error {
+	for _, p := range OnConflictPolicies() {
+		if p == policy {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid conflict policy '%s', expected one of %s",
+		policy, strings.Join(OnConflictPolicies(), ", "))
+}
+
+// onConflict returns the policy for the files of the layout entry that already exist. The entry's own policy comes
+// first, then the one given on the command line, then the one of the profile's layout. Files are skipped by default.
+func (p *Project) onConflict(entry FileEntry) (string, error) {
+	for _, policy := range []string{entry.OnConflict, p.OnConflict, p.Layout.OnConflict} {
+		if policy == "" {
+			continue
+		}
+
+		if err := CheckOnConflict(policy); err != nil {
+			return "", errors.Wrapf(err, "file '%s'", entry.ID)
+		}
+
+		return policy, nil
+	}
+
+	return OnConflictSkip, nil
+}
+
+// planConflict decides what to do with a rendered file that already exists, following the policy.
+// Files identical to the rendered ones are always skipped. When updating, files left as they were generated
+// are always updated.
+func (p *Project) planConflict(root, policy string, f *FileAction) error {
+	if f.Existing == f.Content {
+		return nil
+	}
+
+	if p.Previous != nil {
+		if recorded := p.Previous.file(f.Path); recorded != nil && recorded.Hash == hash(f.Existing) {
+			f.Pristine = true
+			f.Action = ActionOverwrite
+
+			return nil
+		}
+
+		f.Modified = true
+	}
+
+	switch policy {
+	case OnConflictOverwrite:
+		f.Action = ActionOverwrite
+	case OnConflictBackup:
+		f.Action = ActionBackup
+		f.Backup = fmt.Sprintf("%s.%s", f.Path, time.Now().Format(backupFormat))
+	case OnConflictPrompt:
+		f.Action = ActionPrompt
+	case OnConflictFail:
+		f.Action = ActionFail
+	case OnConflictMerge:
+		return p.planMerge(root, f)
+	}
+
+	return nil
+}
+
+// check returns an error listing the files that already exist under the fail policy, if any
+func (pl *Plan) check() error {
+	var failed []string
+
+	for _, f := range pl.Files {
+		if f.Action == ActionFail {
+			failed = append(failed, f.Path)
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("error : aborting, as these files already exist and differ from the rendered ones : %s",
+		strings.Join(failed, ", "))
+}
+
+// prompt shows the differences between each existing file under the prompt policy and the rendered one, and asks
+// whether to overwrite it. Without a way to ask, files are skipped.
+func (p *Project) prompt(pl *Plan) error {
+	for _, f := range pl.Files {
+		if f.Action != ActionPrompt {
+			continue
+		}
+
+		f.Action = ActionSkip
+
+		if p.Confirm == nil {
+			continue
+		}
+
+		fmt.Fprint(p.out(), diff.Unified("a/"+f.Path, "b/"+f.Path, f.Existing, f.Content, 3))
+
+		overwrite, err := p.Confirm(fmt.Sprintf("Overwrite %s", f.Path))
+		if err != nil {
+			return err
+		}
+
+		if overwrite {
+			f.Action = ActionOverwrite
+		}
+	}
+
+	return nil
+}
+
+// overwriteFile writes the rendered file over the existing one, after backing it up if required
+func overwriteFile(t *transaction, f *FileAction) (string, error) {
+	if f.Backup != "" {
+		t.backup(f.Path, f.Backup)
+	}
+
+	if err := t.writeFile(f.Path, f.Content); err != nil {
+		return "", err
+	}
+
+	if err := writeBase(t, f); err != nil {
+		return "", err
+	}
+
+	switch {
+	case f.Backup != "":
+		return fmt.Sprintf("backed up to %s, overwritten.", f.Backup), nil
+	case f.Pristine:
+		return "updated.", nil
+	default:
+		return "overwritten.", nil
+	}
+}
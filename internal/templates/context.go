@@ -0,0 +1,95 @@
+// Package templates holds the template and project building functions
+package templates
+
+import (
+	"path"
+	"strings"
+
+	"github.com/bytemare/goproject/internal/config"
+	"github.com/bytemare/goproject/internal/version"
+)
+
+// Context is the single set of values every template, be it built-in or from the user, is rendered with
+type Context struct {
+	Project ProjectInfo
+	Author  config.Author
+	Git     config.Git
+	CI      CIInfo
+	Docker  config.Docker
+	Sonar   config.Sonar
+	Vars    map[string]interface{}
+
+	// Version is the version of goproject rendering the templates
+	Version string
+}
+
+// ProjectInfo describes the project being generated
+type ProjectInfo struct {
+	Name   string
+	Module string
+	Path   string
+}
+
+// CIInfo describes the continuous integration service of the project
+type CIInfo struct {
+	Provider string
+	URL      string
+}
+
+// context returns the rendering context of the project
+func (p *Project) context() *Context {
+	ctx := &Context{
+		Project: ProjectInfo{
+			Name:   p.Name,
+			Module: p.Module,
+			Path:   p.Path,
+		},
+		Vars:    p.Vars,
+		Version: version.GetVersion(),
+	}
+
+	if p.Author != nil {
+		ctx.Author = *p.Author
+	}
+
+	if prof := p.Profile; prof != nil {
+		if prof.Git != nil {
+			ctx.Git = *prof.Git
+		}
+
+		if prof.Travis != nil && prof.Travis.Profile != "" {
+			ctx.CI = CIInfo{Provider: "travis", URL: prof.Travis.Profile}
+		}
+
+		if prof.Docker != nil {
+			ctx.Docker = *prof.Docker
+		}
+
+		if prof.Sonar != nil {
+			ctx.Sonar = *prof.Sonar
+		}
+	}
+
+	return ctx
+}
+
+// modulePath returns the go module path of a project, derived from the base URL of the developer's repositories
+func modulePath(gitURL, name string) string {
+	base := gitURL
+
+	for _, scheme := range []string{"https://", "http://", "ssh://", "git://"} {
+		base = strings.TrimPrefix(base, scheme)
+	}
+
+	// scp-like addresses, e.g. git@github.com:user
+	if at := strings.Index(base, "@"); at != -1 {
+		base = strings.Replace(base[at+1:], ":", "/", 1)
+	}
+
+	base = strings.TrimSuffix(strings.TrimSuffix(base, "/"), ".git")
+	if base == "" {
+		return name
+	}
+
+	return path.Join(base, name)
+}
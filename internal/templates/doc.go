@@ -3,35 +3,10 @@ package templates
 
 const docIdentifier = "doc"
 
-type doc struct {
-	*file
-	PackageName string
-}
-
 // docConstructor returns the file content populated with the relevant values
 func docConstructor(project *Project) (*file, error) {
-	return newProjectFile(newDoc(project.Name))
-}
-
-func newDoc(packageName string) *doc {
 	f, d, t := docValues()
-
-	return &doc{
-		file:        newFile(docIdentifier, f, d, t),
-		PackageName: packageName,
-	}
-}
-
-func (d *doc) getIdentifier() string {
-	return d.identifier
-}
-
-func (d *doc) getFilename() string {
-	return d.filename
-}
-
-func (d *doc) getTemplate() string {
-	return d.template
+	return newProjectFile(project, newFile(docIdentifier, f, d, t))
 }
 
 func docValues() (f, d, t string) {
@@ -40,10 +15,10 @@ func docValues() (f, d, t string) {
 	const directory = "."
 
 	const template = `/*
-Package {{.PackageName}} [short description]
+Package {{goPackage .Project.Name}} [short description]
 
 */
-package {{.PackageName}}
+package {{goPackage .Project.Name}} // import "{{.Project.Module}}"
 `
 
 	return filename, directory, template
@@ -3,40 +3,10 @@ package templates
 
 const dockerfileIdentifier = "dockerfile"
 
-type dockerfile struct {
-	*file
-	Maintainer string
-	StaticBin  string
-}
-
 // dockerfileConstructor returns the file content populated with the relevant values
 func dockerfileConstructor(project *Project) (*file, error) {
-	conf := project.Profile.Conf
-	maintainer := conf.GetString("docker.maintainer")
-
-	return newProjectFile(newDockerfile(maintainer, project.Name))
-}
-
-func newDockerfile(maintainer, staticbin string) *dockerfile {
 	f, d, t := dockerfileValues()
-
-	return &dockerfile{
-		file:       newFile(dockerfileIdentifier, f, d, t),
-		Maintainer: maintainer,
-		StaticBin:  staticbin,
-	}
-}
-
-func (d *dockerfile) getIdentifier() string {
-	return d.identifier
-}
-
-func (d *dockerfile) getFilename() string {
-	return d.filename
-}
-
-func (d *dockerfile) getTemplate() string {
-	return d.template
+	return newProjectFile(project, newFile(dockerfileIdentifier, f, d, t))
 }
 
 func dockerfileValues() (f, d, t string) {
@@ -48,11 +18,11 @@ func dockerfileValues() (f, d, t string) {
 # but compiling to a go static binary and inserting it is faster and smaller.
 
 FROM gcr.io/distroless/static
-LABEL maintainer="{{.Maintainer}}"
-COPY {{.StaticBin}} {{.StaticBin}}
+LABEL maintainer="{{.Docker.Maintainer}}"
+COPY {{.Project.Name}} {{.Project.Name}}
 RUN echo "nonroot:x:65534:65534:nonroot:/:" > /etc/passwd
 USER nonroot
-ENTRYPOINT ["{{.StaticBin}}"]
+ENTRYPOINT ["{{.Project.Name}}"]
 `
 
 	return filename, directory, template
@@ -0,0 +1,81 @@
+// Package templates holds the template and project building functions
+package templates
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Steps of a build
+const (
+	StepDirectory = "directory"
+	StepFile      = "file"
+	StepCommand   = "command"
+	StepGit       = "git repository"
+)
+
+// Failure is a step of a build that failed : the creation of a directory, the rendering or writing of a file,
+// or a command. In an undo, it is the removal of a directory, a file or the git repository.
+type Failure struct {
+	Step string
+	Name string
+	Err  error
+}
+
+// BuildError aggregates the failures of a build. Written tells whether the steps that succeeded were written to the
+// project nonetheless, or if nothing was written.
+type BuildError struct {
+	Failures []*Failure
+	Written  bool
+}
+
+// Error returns a summary of the failures
+func (e *BuildError) Error() string {
+	var sb strings.Builder
+
+	if e.Written {
+		fmt.Fprintf(&sb, "error : %d build steps failed, the others were written :", len(e.Failures))
+	} else {
+		fmt.Fprintf(&sb, "error : %d build steps failed, nothing was written :", len(e.Failures))
+	}
+
+	for _, f := range e.Failures {
+		fmt.Fprintf(&sb, "\n\t- %s %s : %v", f.Step, f.Name, f.Err)
+	}
+
+	return sb.String()
+}
+
+// UndoError aggregates the failures of an undo. The steps that succeeded were undone nonetheless.
+type UndoError struct {
+	Failures []*Failure
+}
+
+// Error returns a summary of the failures
+func (e *UndoError) Error() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "error : %d undo steps failed, the others were undone :", len(e.Failures))
+
+	for _, f := range e.Failures {
+		fmt.Fprintf(&sb, "\n\t- %s %s : %v", f.Step, f.Name, f.Err)
+	}
+
+	return sb.String()
+}
+
+// failures collects the failures of a build
+type failures []*Failure
+
+func (fs *failures) add(step, name string, err error) {
+	*fs = append(*fs, &Failure{Step: step, Name: name, Err: err})
+}
+
+// err returns the failures as a BuildError, or nil if there were none
+func (fs failures) err(written bool) error {
+	if len(fs) == 0 {
+		return nil
+	}
+
+	return &BuildError{Failures: fs, Written: written}
+}
@@ -0,0 +1,418 @@
+// Package templates holds the template and project building functions
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// funcMap returns the functions available to every built-in and user template, in addition to the text/template
+// built-in functions.
+//
+// Strings
+//
+//	lower, upper, title, trim, trimPrefix, trimSuffix, replace, contains, hasPrefix, hasSuffix, repeat, quote
+//	split SEP S, join SEP LIST
+//	camelCase, pascalCase, snakeCase, kebabCase
+//
+// Dates
+//
+//	now, year, date LAYOUT
+//
+// Paths
+//
+//	base, dir, ext, clean, pathJoin ELEM...
+//
+// Semantic versions
+//
+//	semver V (.Major, .Minor, .Patch, .Pre), semverCompare A B, majorMinor V
+//
+// Go identifiers
+//
+//	goIdent S, goPackage S
+//
+// Values
+//
+//	default DEFAULT VALUE, required MESSAGE VALUE
+func funcMap() template.FuncMap {
+	return template.FuncMap{
+		// Strings
+		"lower":      strings.ToLower,
+		"upper":      strings.ToUpper,
+		"title":      title,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"repeat":     func(count int, s string) string { return strings.Repeat(s, count) },
+		"quote":      strconv.Quote,
+		"split":      func(sep, s string) []string { return strings.Split(s, sep) },
+		"join":       join,
+		"camelCase":  camelCase,
+		"pascalCase": pascalCase,
+		"snakeCase":  func(s string) string { return strings.Join(words(s), "_") },
+		"kebabCase":  func(s string) string { return strings.Join(words(s), "-") },
+
+		// Dates
+		"now":  time.Now,
+		"year": func() int { return time.Now().Year() },
+		"date": func(layout string) string { return time.Now().Format(layout) },
+
+		// Paths
+		"base":     path.Base,
+		"dir":      path.Dir,
+		"ext":      path.Ext,
+		"clean":    path.Clean,
+		"pathJoin": path.Join,
+
+		// Semantic versions
+		"semver":        parseSemver,
+		"semverCompare": semverCompare,
+		"majorMinor":    majorMinor,
+
+		// Go identifiers
+		"goIdent":   goIdent,
+		"goPackage": goPackage,
+
+		// Values
+		"default":  defaultValue,
+		"required": required,
+	}
+}
+
+// words splits a string into lower case words, on non alphanumeric characters and on case changes
+func words(s string) []string {
+	var (
+		res  []string
+		word []rune
+	)
+
+	runes := []rune(s)
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) != 0 {
+				res = append(res, string(word))
+				word = nil
+			}
+
+			continue
+		}
+
+		// A new word starts on an upper case letter following a lower case one, or preceding one in an acronym
+		if unicode.IsUpper(r) && len(word) != 0 {
+			prev := runes[i-1]
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(prev)) {
+				res = append(res, string(word))
+				word = nil
+			}
+		}
+
+		word = append(word, unicode.ToLower(r))
+	}
+
+	if len(word) != 0 {
+		res = append(res, string(word))
+	}
+
+	return res
+}
+
+// title upper cases the first letter of every word, leaving the rest untouched
+func title(s string) string {
+	prev := ' '
+
+	return strings.Map(func(r rune) rune {
+		defer func() { prev = r }()
+
+		if unicode.IsSpace(prev) || prev == '-' || prev == '_' {
+			return unicode.ToTitle(r)
+		}
+
+		return r
+	}, s)
+}
+
+func pascalCase(s string) string {
+	var b strings.Builder
+
+	for _, w := range words(s) {
+		r := []rune(w)
+		b.WriteString(strings.ToUpper(string(r[0])) + string(r[1:]))
+	}
+
+	return b.String()
+}
+
+func camelCase(s string) string {
+	p := []rune(pascalCase(s))
+	if len(p) == 0 {
+		return ""
+	}
+
+	return strings.ToLower(string(p[0])) + string(p[1:])
+}
+
+// join concatenates the elements of a list, whatever their type, with the given separator
+func join(sep string, list interface{}) (string, error) {
+	if l, ok := list.([]string); ok {
+		return strings.Join(l, sep), nil
+	}
+
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return "", fmt.Errorf("join : can't join elements of type %T", list)
+	}
+
+	elems := make([]string, v.Len())
+	for i := range elems {
+		elems[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+
+	return strings.Join(elems, sep), nil
+}
+
+// goIdent turns a string into a valid Go identifier, replacing invalid characters with underscores
+func goIdent(s string) string {
+	ident := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+
+		return '_'
+	}, s)
+
+	if ident == "" || unicode.IsDigit([]rune(ident)[0]) {
+		ident = "_" + ident
+	}
+
+	if token.IsKeyword(ident) {
+		ident += "_"
+	}
+
+	return ident
+}
+
+// goPackage turns a string into a conventional Go package name : lower case letters and digits only
+func goPackage(s string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToLower(r)
+		}
+
+		return -1
+	}, path.Base(s))
+
+	name = strings.TrimLeftFunc(name, unicode.IsDigit)
+	if name == "" || token.IsKeyword(name) {
+		name = "pkg" + name
+	}
+
+	return name
+}
+
+// semver holds the components of a semantic version
+type semver struct {
+	Major int
+	Minor int
+	Patch int
+	Pre   string
+}
+
+// String returns the canonical representation of the version, without the 'v' prefix
+func (s *semver) String() string {
+	v := fmt.Sprintf("%d.%d.%d", s.Major, s.Minor, s.Patch)
+	if s.Pre != "" {
+		v += "-" + s.Pre
+	}
+
+	return v
+}
+
+// parseSemver parses a version like v1.2.3-rc1, where the 'v' prefix, and minor and patch numbers are optional
+func parseSemver(version string) (*semver, error) {
+	v := strings.TrimPrefix(strings.TrimSpace(version), "v")
+
+	// Build metadata is ignored
+	v = strings.SplitN(v, "+", 2)[0]
+
+	var s semver
+
+	parts := strings.SplitN(v, "-", 2)
+	if len(parts) == 2 {
+		s.Pre = parts[1]
+	}
+
+	numbers := strings.Split(parts[0], ".")
+	if len(numbers) > 3 {
+		return nil, fmt.Errorf("semver : invalid version '%s'", version)
+	}
+
+	fields := []*int{&s.Major, &s.Minor, &s.Patch}
+
+	for i, n := range numbers {
+		val, err := strconv.Atoi(n)
+		if err != nil || val < 0 {
+			return nil, fmt.Errorf("semver : invalid version '%s'", version)
+		}
+
+		*fields[i] = val
+	}
+
+	return &s, nil
+}
+
+// semverCompare returns -1, 0 or 1 whether a is lower, equal or greater than b
+func semverCompare(a, b string) (int, error) {
+	va, err := parseSemver(a)
+	if err != nil {
+		return 0, err
+	}
+
+	vb, err := parseSemver(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, d := range []int{va.Major - vb.Major, va.Minor - vb.Minor, va.Patch - vb.Patch} {
+		if d != 0 {
+			return d / abs(d), nil
+		}
+	}
+
+	// A pre-release has a lower precedence than the associated normal version
+	switch {
+	case va.Pre == vb.Pre:
+		return 0, nil
+	case va.Pre == "":
+		return 1, nil
+	case vb.Pre == "":
+		return -1, nil
+	default:
+		return comparePre(va.Pre, vb.Pre), nil
+	}
+}
+
+// comparePre compares pre-release versions identifier by identifier, as the semantic versioning specification says :
+// numeric identifiers are compared numerically and are lower than alphanumeric ones, which are compared in ASCII
+// order, and a version with more identifiers is greater if all preceding ones are equal.
+func comparePre(a, b string) int {
+	ia, ib := strings.Split(a, "."), strings.Split(b, ".")
+
+	for i := 0; i < len(ia) && i < len(ib); i++ {
+		if c := compareIdentifier(ia[i], ib[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(ia) < len(ib):
+		return -1
+	case len(ia) > len(ib):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareIdentifier(a, b string) int {
+	na, nb := numeric(a), numeric(b)
+
+	switch {
+	case na && !nb:
+		return -1
+	case !na && nb:
+		return 1
+	case na && len(a) != len(b):
+		// Numeric identifiers have no leading zeroes : the longer is the greater
+		if len(a) < len(b) {
+			return -1
+		}
+
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
+
+// numeric returns whether the identifier only has digits
+func numeric(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// majorMinor returns the major and minor components of a version, e.g. 1.14 for 1.14.2
+func majorMinor(version string) (string, error) {
+	v, err := parseSemver(version)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor), nil
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+
+	return i
+}
+
+// empty returns whether the value is the zero value of its type, or an empty collection
+func empty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+// defaultValue returns the value if it is not empty, and the default otherwise. Meant to be used in pipelines :
+// {{ .Vars.license | default "MIT" }}
+func defaultValue(def, value interface{}) interface{} {
+	if empty(value) {
+		return def
+	}
+
+	return value
+}
+
+// required fails the template execution with the given message if the value is empty
+func required(message string, value interface{}) (interface{}, error) {
+	if empty(value) {
+		return nil, errors.New(message)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,230 @@
+package templates
+
+import (
+	"testing"
+)
+
+func TestCaseConversion(t *testing.T) {
+	tests := []struct {
+		in                          string
+		camel, pascal, snake, kebab string
+	}{
+		{"", "", "", "", ""},
+		{"myApp", "myApp", "MyApp", "my_app", "my-app"},
+		{"my-app", "myApp", "MyApp", "my_app", "my-app"},
+		{"my_app v2", "myAppV2", "MyAppV2", "my_app_v2", "my-app-v2"},
+		{"HTTPServer", "httpServer", "HttpServer", "http_server", "http-server"},
+		{"parseJSON", "parseJson", "ParseJson", "parse_json", "parse-json"},
+		{"go1Project", "go1Project", "Go1Project", "go1_project", "go1-project"},
+	}
+
+	funcs := funcMap()
+	snake := funcs["snakeCase"].(func(string) string)
+	kebab := funcs["kebabCase"].(func(string) string)
+
+	for _, tt := range tests {
+		if got := camelCase(tt.in); got != tt.camel {
+			t.Errorf("camelCase(%q) = %q, want %q", tt.in, got, tt.camel)
+		}
+
+		if got := pascalCase(tt.in); got != tt.pascal {
+			t.Errorf("pascalCase(%q) = %q, want %q", tt.in, got, tt.pascal)
+		}
+
+		if got := snake(tt.in); got != tt.snake {
+			t.Errorf("snakeCase(%q) = %q, want %q", tt.in, got, tt.snake)
+		}
+
+		if got := kebab(tt.in); got != tt.kebab {
+			t.Errorf("kebabCase(%q) = %q, want %q", tt.in, got, tt.kebab)
+		}
+	}
+}
+
+func TestTitle(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"hello world", "Hello World"},
+		{"my-app_name", "My-App_Name"},
+		{"keep UPPER", "Keep UPPER"},
+	}
+
+	for _, tt := range tests {
+		if got := title(tt.in); got != tt.want {
+			t.Errorf("title(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGoIdent(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"app", "app"},
+		{"my-app", "my_app"},
+		{"my.app v2", "my_app_v2"},
+		{"1app", "_1app"},
+		{"", "_"},
+		{"func", "func_"},
+	}
+
+	for _, tt := range tests {
+		if got := goIdent(tt.in); got != tt.want {
+			t.Errorf("goIdent(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGoPackage(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"app", "app"},
+		{"My-App", "myapp"},
+		{"github.com/org/go_lib", "golib"},
+		{"2fa", "fa"},
+		{"123", "pkg"},
+		{"type", "pkgtype"},
+		{"été", "t"},
+	}
+
+	for _, tt := range tests {
+		if got := goPackage(tt.in); got != tt.want {
+			t.Errorf("goPackage(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		err  bool
+	}{
+		{in: "1.2.3", want: "1.2.3"},
+		{in: "v1.2.3", want: "1.2.3"},
+		{in: "v1.14", want: "1.14.0"},
+		{in: "2", want: "2.0.0"},
+		{in: "1.0.0-rc.1", want: "1.0.0-rc.1"},
+		{in: "1.0.0-rc.1+build.5", want: "1.0.0-rc.1"},
+		{in: "1.2.3.4", err: true},
+		{in: "1.x.3", err: true},
+		{in: "", err: true},
+	}
+
+	for _, tt := range tests {
+		v, err := parseSemver(tt.in)
+
+		switch {
+		case tt.err && err == nil:
+			t.Errorf("parseSemver(%q) = %v, want an error", tt.in, v)
+		case !tt.err && err != nil:
+			t.Errorf("parseSemver(%q) : unexpected error %v", tt.in, err)
+		case !tt.err && v.String() != tt.want:
+			t.Errorf("parseSemver(%q) = %s, want %s", tt.in, v, tt.want)
+		}
+	}
+}
+
+func TestSemverCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.3+build", 0},
+		{"1.2.3", "1.2.4", -1},
+		{"1.10.0", "1.9.0", 1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0", "1.0.0-rc.1", 1},
+		{"1.0.0-rc.10", "1.0.0-rc.2", 1},
+		{"1.0.0-rc.2", "1.0.0-rc.10", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-alpha.beta", "1.0.0-beta", -1},
+		{"1.0.0-beta.11", "1.0.0-rc.1", -1},
+		{"1.0.0-1", "1.0.0-alpha", -1},
+		{"1.0.0-rc.1", "1.0.0-rc.1", 0},
+	}
+
+	for _, tt := range tests {
+		got, err := semverCompare(tt.a, tt.b)
+		if err != nil {
+			t.Fatalf("semverCompare(%q, %q) : %v", tt.a, tt.b, err)
+		}
+
+		if got != tt.want {
+			t.Errorf("semverCompare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	if _, err := semverCompare("1.0.0", "one"); err == nil {
+		t.Error("semverCompare of an invalid version succeeded")
+	}
+}
+
+func TestMajorMinor(t *testing.T) {
+	if got, err := majorMinor("go1.14.2"); err == nil {
+		t.Errorf("majorMinor(go1.14.2) = %q, want an error", got)
+	}
+
+	if got, err := majorMinor("v1.14.2"); err != nil || got != "1.14" {
+		t.Errorf("majorMinor(v1.14.2) = %q, %v, want 1.14", got, err)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		list interface{}
+		want string
+		err  bool
+	}{
+		{list: []string{"a", "b"}, want: "a,b"},
+		{list: []interface{}{"a", 1, true}, want: "a,1,true"},
+		{list: []int{}, want: ""},
+		{list: "a", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := join(",", tt.list)
+
+		if tt.err != (err != nil) || got != tt.want {
+			t.Errorf("join(%v) = %q, %v, want %q", tt.list, got, err, tt.want)
+		}
+	}
+}
+
+func TestDefaultAndRequired(t *testing.T) {
+	var nilMap map[string]string
+
+	tests := []struct {
+		value interface{}
+		empty bool
+	}{
+		{nil, true},
+		{"", true},
+		{0, true},
+		{false, true},
+		{[]string{}, true},
+		{nilMap, true},
+		{"MIT", false},
+		{1, false},
+		{true, false},
+		{[]string{"a"}, false},
+	}
+
+	for _, tt := range tests {
+		got := defaultValue("def", tt.value)
+		if tt.empty && got != "def" || !tt.empty && got == "def" {
+			t.Errorf("default \"def\" %#v = %#v", tt.value, got)
+		}
+
+		_, err := required("missing", tt.value)
+		if tt.empty != (err != nil) {
+			t.Errorf("required %#v : got error %v", tt.value, err)
+		}
+	}
+}
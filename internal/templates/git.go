@@ -0,0 +1,162 @@
+// Package templates holds the template and project building functions
+package templates
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bytemare/goproject/internal/config"
+	"github.com/bytemare/goproject/internal/vfs"
+
+	"github.com/pkg/errors"
+)
+
+// defaultCommitMessage is the message of the initial commit if the profile does not set one
+const defaultCommitMessage = "Initial commit"
+
+// planGit returns the commands initialising the git repository of the project : the repository itself, its initial
+// branch, the developer's identity, the origin remote, and the initial commit. If the project already has a
+// repository, they are all skipped.
+func (p *Project) planGit(root string) []*CommandAction {
+	var settings config.Git
+	if p.Profile != nil && p.Profile.Git != nil {
+		settings = *p.Profile.Git
+	}
+
+	gitInit := &CommandAction{
+		Description: "Initialising git.",
+		Args:        []string{"git", "init"},
+		Action:      ActionRun,
+		stage: func(t *transaction) error {
+			t.journal.GitInit = true
+
+			if err := excludeState(t.fs, filepath.Join(t.staging, ".git")); err != nil {
+				return err
+			}
+
+			return t.stage(".git", "")
+		},
+	}
+
+	commands := []*CommandAction{gitInit}
+
+	// The following commands only change the repository, which is already staged
+	add := func(description string, args ...string) *CommandAction {
+		c := &CommandAction{
+			Description: description,
+			Args:        append([]string{"git"}, args...),
+			Action:      ActionRun,
+			requires:    gitInit,
+			stage:       func(t *transaction) error { return nil },
+		}
+		commands = append(commands, c)
+
+		return c
+	}
+
+	if settings.Branch != "" {
+		add("Setting the initial git branch.", "symbolic-ref", "HEAD", "refs/heads/"+settings.Branch)
+	}
+
+	if settings.User != "" {
+		add("Setting the git user name.", "config", "user.name", settings.User)
+	}
+
+	if settings.Mail != "" {
+		add("Setting the git user email.", "config", "user.email", settings.Mail)
+	}
+
+	if settings.URL != "" {
+		add("Adding the origin git remote.", "remote", "add", "origin", remoteURL(settings.URL, p.Name))
+	}
+
+	if settings.Commit {
+		message := settings.Message
+		if message == "" {
+			message = defaultCommitMessage
+		}
+
+		add("Staging the project files in git.", "add", "--all")
+
+		commit := add("Creating the initial commit.", "commit", "--quiet", "--message", message)
+		commit.stage = func(t *transaction) error {
+			head, err := headCommit(t.fs, filepath.Join(t.staging, ".git"))
+			if err != nil {
+				return err
+			}
+
+			t.journal.GitCommit = head
+
+			return nil
+		}
+	}
+
+	if e, _ := vfs.Exists(p.fs(), filepath.Join(root, ".git")); e {
+		for _, c := range commands {
+			c.Action = ActionSkip
+			c.Reason = "Git directory (.git) already exists. Skipping initialisation."
+		}
+	}
+
+	return commands
+}
+
+// excludeState keeps goproject's local state, the journal and the bases of merges, out of the git repository
+func excludeState(fs vfs.FS, gitDir string) error {
+	name := filepath.Join(gitDir, "info", "exclude")
+
+	content, err := fs.ReadFile(name)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "could not read git exclude file")
+	}
+
+	if len(content) != 0 && !bytes.HasSuffix(content, []byte("\n")) {
+		content = append(content, '\n')
+	}
+
+	content = append(content, "/"+path.Dir(journalName)+"/\n"...)
+
+	if err := fs.MkdirAll(filepath.Dir(name), config.DirMode); err != nil {
+		return errors.Wrap(err, "could not write git exclude file")
+	}
+
+	return errors.Wrap(fs.WriteFile(name, content, config.FileMode), "could not write git exclude file")
+}
+
+// remoteURL returns the URL of the remote repository of a project, from the base URL of the developer's repositories.
+// Base URLs with neither a scheme nor an scp-like user@host: prefix are taken as https.
+func remoteURL(gitURL, name string) string {
+	base := strings.TrimSuffix(gitURL, "/")
+
+	if !strings.Contains(base, "://") && !strings.Contains(base, "@") {
+		base = "https://" + base
+	}
+
+	return base + "/" + name + ".git"
+}
+
+// headCommit returns the hash of the commit HEAD points to, in the git directory
+func headCommit(fs vfs.FS, gitDir string) (string, error) {
+	head, err := fs.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", errors.Wrap(err, "could not read git HEAD")
+	}
+
+	ref := strings.TrimSpace(string(head))
+	if !strings.HasPrefix(ref, "ref: ") {
+		// Detached HEAD
+		return ref, nil
+	}
+
+	ref = strings.TrimPrefix(ref, "ref: ")
+
+	commit, err := fs.ReadFile(filepath.Join(gitDir, filepath.FromSlash(ref)))
+	if err != nil {
+		return "", errors.Wrapf(err, "could not read git reference '%s'", ref)
+	}
+
+	return strings.TrimSpace(string(commit)), nil
+}
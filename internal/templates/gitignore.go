@@ -4,9 +4,9 @@ package templates
 const gitignoreIdentifier = "gitignore"
 
 // gitignoreConstructor returns the file content populated with the relevant values
-func gitignoreConstructor(project *Project) (*file, error) { //nolint:unparam // project is not needed when no variables
+func gitignoreConstructor(project *Project) (*file, error) {
 	f, d, t := gitignoreValues()
-	return newProjectFile(newFile(gitignoreIdentifier, f, d, t))
+	return newProjectFile(project, newFile(gitignoreIdentifier, f, d, t))
 }
 
 func gitignoreValues() (f, d, t string) {
@@ -33,6 +33,9 @@ func gitignoreValues() (f, d, t string) {
 # Directories
 bin
 coverage
+
+# goproject's local state : the journal of the last generation, and the bases of merges
+.goproject/
 `
 
 	return filename, directory, template
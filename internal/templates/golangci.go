@@ -4,9 +4,9 @@ package templates
 const golangciIdentifier = "golangci"
 
 // golangciConstructor returns the file content populated with the relevant values
-func golangciConstructor(project *Project) (*file, error) { //nolint:unparam // project is not needed when no variables
+func golangciConstructor(project *Project) (*file, error) {
 	i, d, t := golangciValues()
-	return newProjectFile(newFile(golangciIdentifier, i, d, t))
+	return newProjectFile(project, newFile(golangciIdentifier, i, d, t))
 }
 
 func golangciValues() (f, d, t string) { //nolint:funlen // length is due to a constant, no complexity here
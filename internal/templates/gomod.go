@@ -0,0 +1,51 @@
+// Package templates holds the template and project building functions
+package templates
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bytemare/goproject/internal/vfs"
+
+	"github.com/pkg/errors"
+)
+
+// readModule returns the module path declared in the go.mod file, or an empty string if there is no such file
+func readModule(fs vfs.FS, name string) (string, error) {
+	content, err := fs.ReadFile(name)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", errors.Wrapf(err, "could not read '%s'", name)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != "module" {
+			continue
+		}
+
+		module := fields[1]
+		if strings.HasPrefix(module, `"`) || strings.HasPrefix(module, "`") {
+			if module, err = strconv.Unquote(module); err != nil {
+				return "", errors.Wrapf(err, "invalid module path in '%s'", name)
+			}
+		}
+
+		return module, nil
+	}
+
+	return "", errors.Errorf("no module path declared in '%s'", name)
+}
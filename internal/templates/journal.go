@@ -0,0 +1,102 @@
+// Package templates holds the template and project building functions
+package templates
+
+import (
+	"bytes"
+	"path"
+	"path/filepath"
+
+	"github.com/bytemare/goproject/internal/vfs"
+
+	"github.com/pelletier/go-toml"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// journalName is the journal of the last generation, relative to the project
+const journalName = ".goproject/journal.toml"
+
+const journalHeader = "# This file records what the last goproject generation created, to undo it. Do not edit.\n\n"
+
+// journal records the directories and files a generation created, so that it can be undone.
+// Files and directories that already existed are not recorded.
+type journal struct {
+	// Root is the absolute path of the project directory, if the generation created it
+	Root string
+
+	// Directories and files created, relative to the project, in order of creation
+	Directories []string
+	Files       []*JournalFile
+
+	// GoModInit and GitInit tell whether the commands initialising go modules and git ran
+	GoModInit bool `mapstructure:"go-mod-init"`
+	GitInit   bool `mapstructure:"git-init"`
+
+	// GitCommit is the hash of the initial commit, if the generation created one
+	GitCommit string `mapstructure:"git-commit"`
+}
+
+// JournalFile records a file created by a generation, and the hash of its content at that time
+type JournalFile struct {
+	Path string
+	Hash string `mapstructure:"sha256"`
+}
+
+// record records a created file
+func (j *journal) record(name, content string) {
+	j.Files = append(j.Files, &JournalFile{Path: path.Clean(name), Hash: hash(content)})
+}
+
+// encode returns the journal as toml
+func (j *journal) encode() (string, error) {
+	files := make([]map[string]interface{}, len(j.Files))
+	for i, f := range j.Files {
+		files[i] = map[string]interface{}{
+			"path":   f.Path,
+			"sha256": f.Hash,
+		}
+	}
+
+	values := map[string]interface{}{
+		"root":        j.Root,
+		"directories": j.Directories,
+		"files":       files,
+		"go-mod-init": j.GoModInit,
+		"git-init":    j.GitInit,
+		"git-commit":  j.GitCommit,
+	}
+
+	tree, err := toml.TreeFromMap(values)
+	if err != nil {
+		return "", errors.Wrap(err, "could not encode journal")
+	}
+
+	content, err := tree.ToTomlString()
+	if err != nil {
+		return "", errors.Wrap(err, "could not encode journal")
+	}
+
+	return journalHeader + content, nil
+}
+
+// readJournal reads the journal of the last generation of the project in the directory, on the file system
+func readJournal(fs vfs.FS, dir string) (*journal, error) {
+	content, err := fs.ReadFile(filepath.Join(dir, journalName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read journal of '%s'", dir)
+	}
+
+	v := viper.New()
+	v.SetConfigType("toml")
+
+	if err := v.ReadConfig(bytes.NewReader(content)); err != nil {
+		return nil, errors.Wrapf(err, "could not read journal of '%s'", dir)
+	}
+
+	var j journal
+	if err := v.Unmarshal(&j); err != nil {
+		return nil, errors.Wrapf(err, "could not decode journal of '%s'", dir)
+	}
+
+	return &j, nil
+}
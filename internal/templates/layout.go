@@ -0,0 +1,185 @@
+// Package templates holds the template and project building functions
+package templates
+
+import (
+	"bytes"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/bytemare/goproject/internal/vfs"
+
+	"github.com/pkg/errors"
+)
+
+// layout represents the directory and file layout of the Project
+type layout struct {
+	Directories []DirectoryEntry
+	Files       []FileEntry
+
+	// OnConflict is the policy for the files that already exist, unless given on the command line
+	OnConflict string `mapstructure:"on-conflict"`
+}
+
+// DirectoryEntry is a directory of the layout, only created if its condition, if any, holds
+type DirectoryEntry struct {
+	Path string
+	When string
+}
+
+// FileEntry is a template of the layout, only rendered if its condition, if any, holds.
+// Its policy for files that already exist, if any, prevails over any other.
+type FileEntry struct {
+	ID         string
+	When       string
+	OnConflict string `mapstructure:"on-conflict"`
+}
+
+// String returns the path of the directory, and its condition
+func (d DirectoryEntry) String() string {
+	return entryString(d.Path, d.When)
+}
+
+// String returns the identifier of the template, and its condition
+func (f FileEntry) String() string {
+	return entryString(f.ID, f.When)
+}
+
+func entryString(name, when string) string {
+	if when == "" {
+		return name
+	}
+
+	return name + " (when " + when + ")"
+}
+
+// layoutEntryHook lets layout entries be given as plain strings in profiles, as they were before conditions existed
+func layoutEntryHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.String {
+		return data, nil
+	}
+
+	switch to {
+	case reflect.TypeOf(DirectoryEntry{}):
+		return DirectoryEntry{Path: data.(string)}, nil
+	case reflect.TypeOf(FileEntry{}):
+		return FileEntry{ID: data.(string)}, nil
+	default:
+		return data, nil
+	}
+}
+
+// selection holds the layout entries retained for the build, and is used to evaluate the conditions of the others
+type selection struct {
+	project     *Project
+	root        string
+	directories []string
+	files       []string
+}
+
+// hasDir returns whether the directory is part of the selected layout, or already exists in the project
+func (s *selection) hasDir(dir string) bool {
+	dir = path.Clean(dir)
+
+	for _, d := range s.directories {
+		if path.Clean(d) == dir {
+			return true
+		}
+	}
+
+	e, err := vfs.Exists(s.project.fs(), filepath.Join(s.root, dir))
+
+	return e && err == nil
+}
+
+// hasFile returns whether the template is part of the selected layout
+func (s *selection) hasFile(id string) bool {
+	for _, f := range s.files {
+		if f == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// holds evaluates a condition. A condition is a template pipeline, evaluated with the same values and functions as
+// templates, as well as the hasDir and hasFile functions, e.g. 'and .Vars.opensource (hasDir "cmd")'.
+// As a shorthand, 'vars.opensource' stands for '.Vars.opensource'.
+func (s *selection) holds(when string) (bool, error) {
+	if strings.HasPrefix(when, "vars.") {
+		when = ".Vars." + strings.TrimPrefix(when, "vars.")
+	}
+
+	tmpl, err := template.New("when").Funcs(funcMap()).Funcs(template.FuncMap{
+		"hasDir":  s.hasDir,
+		"hasFile": s.hasFile,
+	}).Parse("{{if " + when + "}}true{{end}}")
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid condition '%s'", when)
+	}
+
+	var buff bytes.Buffer
+	if err := tmpl.Execute(&buff, s.project.context()); err != nil {
+		return false, errors.Wrapf(err, "could not evaluate condition '%s'", when)
+	}
+
+	return buff.String() == "true", nil
+}
+
+// resolveLayout returns the directories and templates to build. Entries without conditions are always retained.
+// Conditions are evaluated in declaration order, directories first, and see the entries without conditions
+// as well as the conditional entries retained before them. Existing directories are looked up from the root.
+func (p *Project) resolveLayout(root string) (dirs []string, files []FileEntry, err error) {
+	s := &selection{project: p, root: root}
+
+	for _, d := range p.Layout.Directories {
+		if d.When == "" {
+			s.directories = append(s.directories, d.Path)
+		}
+	}
+
+	for _, f := range p.Layout.Files {
+		if f.When == "" {
+			s.files = append(s.files, f.ID)
+		}
+	}
+
+	for _, d := range p.Layout.Directories {
+		ok := d.When == ""
+		if !ok {
+			if ok, err = s.holds(d.When); err != nil {
+				return nil, nil, errors.Wrapf(err, "directory '%s'", d.Path)
+			}
+
+			if ok {
+				s.directories = append(s.directories, d.Path)
+			}
+		}
+
+		if ok {
+			dirs = append(dirs, d.Path)
+		}
+	}
+
+	for _, f := range p.Layout.Files {
+		ok := f.When == ""
+		if !ok {
+			if ok, err = s.holds(f.When); err != nil {
+				return nil, nil, errors.Wrapf(err, "file '%s'", f.ID)
+			}
+
+			if ok {
+				s.files = append(s.files, f.ID)
+			}
+		}
+
+		if ok {
+			files = append(files, f)
+		}
+	}
+
+	return dirs, files, nil
+}
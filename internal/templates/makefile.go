@@ -4,9 +4,9 @@ package templates
 const makefileIdentifier = "makefile"
 
 // makefileConstructor returns the file content populated with the relevant values
-func makefileConstructor(project *Project) (*file, error) { //nolint:unparam // project is not needed when no variables
+func makefileConstructor(project *Project) (*file, error) {
 	f, d, t := makefileValues()
-	return newProjectFile(newFile(makefileIdentifier, f, d, t))
+	return newProjectFile(project, newFile(makefileIdentifier, f, d, t))
 }
 
 func makefileValues() (f, d, t string) { //nolint:funlen // length is due to a constant, no complexity here
@@ -22,7 +22,7 @@ func makefileValues() (f, d, t string) { //nolint:funlen // length is due to a c
 TARGETS  := ""
 
 # Project path and name
-PROJECT_REPO := $(shell go list -m)
+PROJECT_REPO := {{.Project.Module}}
 PROJECT_NAME := $(shell basename $(PROJECT_REPO))
 BINARY  :=  $(PROJECT_NAME)
 
@@ -81,7 +81,7 @@ all:
 .PHONY: prepare-lint
 prepare-lint:
 	@echo "Installing golangci-lint ..."
-	@curl -sSfL https://raw.githubusercontent.com/golangci/golangci-lint/master/install.sh | sh -s -- -b $(GOPATH)/bin latest
+	@curl -sSfL https://raw.githubusercontent.com/golangci/golangci-lint/master/install.sh | sh -s -- -b $(GOPATH)/bin {{template "golangci-lint-version"}}
 
 .PHONY: prepare-python3
 prepare-python3:
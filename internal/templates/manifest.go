@@ -0,0 +1,158 @@
+// Package templates holds the template and project building functions
+package templates
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+
+	"github.com/bytemare/goproject/internal/version"
+
+	"github.com/pelletier/go-toml"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// ManifestName is the name of the manifest recorded in the generated project
+const ManifestName = ".goproject.lock"
+
+const manifestHeader = "# This file records how goproject generated the project, and is updated by goproject. Do not edit.\n\n"
+
+// Manifest records how the project was generated : the version of goproject, the profile, the variables given on the
+// command line or in the wizard, and the files generated. The variables of the profile are not recorded, so that
+// updates render the current ones.
+type Manifest struct {
+	Version string
+	Profile string
+	Name    string
+	Module  string
+	Vars    map[string]interface{}
+	Files   []*ManifestFile
+}
+
+// ManifestFile records a generated file, the template it was rendered from, and the hash of the rendered content.
+// A file whose content has the same hash has not been modified since.
+type ManifestFile struct {
+	Path     string
+	Template string
+	Hash     string `mapstructure:"sha256"`
+}
+
+// hash returns the hexadecimal sha256 hash of the content
+func hash(content string) string {
+	h := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(h[:])
+}
+
+// manifest returns the manifest of the planned generation. It records the rendered files that were written or merged,
+// and those already identical to the rendered ones. Existing files that were skipped are only recorded if a previous
+// generation did, as they were.
+func (p *Project) manifest(plan *Plan) *Manifest {
+	m := &Manifest{
+		Version: version.GetVersion(),
+		Profile: p.Profile.Name,
+		Name:    p.Name,
+		Module:  p.Module,
+		Vars:    p.givenVars(),
+		Files:   make([]*ManifestFile, 0, len(plan.Files)),
+	}
+
+	for _, f := range plan.Files {
+		if f.Action == ActionError {
+			continue
+		}
+
+		if f.Action == ActionSkip && f.Existing != f.Content {
+			if p.Previous != nil {
+				if recorded := p.Previous.file(f.Path); recorded != nil {
+					m.Files = append(m.Files, recorded)
+				}
+			}
+
+			continue
+		}
+
+		m.Files = append(m.Files, &ManifestFile{
+			Path:     f.Path,
+			Template: f.ID,
+			Hash:     hash(f.Content),
+		})
+	}
+
+	return m
+}
+
+// givenVars returns the variables of the project that were not taken from the profile, but given or changed since
+func (p *Project) givenVars() map[string]interface{} {
+	vars := make(map[string]interface{})
+
+	for k, v := range p.Vars {
+		if pv, ok := p.profileVars[k]; !ok || !reflect.DeepEqual(pv, v) {
+			vars[k] = v
+		}
+	}
+
+	return vars
+}
+
+// file returns the record of the file at the given path, or nil if it was not generated
+func (m *Manifest) file(path string) *ManifestFile {
+	for _, f := range m.Files {
+		if f.Path == path {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// encode returns the manifest as toml
+func (m *Manifest) encode() (string, error) {
+	files := make([]map[string]interface{}, len(m.Files))
+	for i, f := range m.Files {
+		files[i] = map[string]interface{}{
+			"path":     f.Path,
+			"template": f.Template,
+			"sha256":   f.Hash,
+		}
+	}
+
+	values := map[string]interface{}{
+		"version": m.Version,
+		"profile": m.Profile,
+		"name":    m.Name,
+		"module":  m.Module,
+		"vars":    m.Vars,
+		"files":   files,
+	}
+
+	tree, err := toml.TreeFromMap(values)
+	if err != nil {
+		return "", errors.Wrap(err, "could not encode manifest")
+	}
+
+	content, err := tree.ToTomlString()
+	if err != nil {
+		return "", errors.Wrap(err, "could not encode manifest")
+	}
+
+	return manifestHeader + content, nil
+}
+
+// ReadManifest reads the manifest from the file
+func ReadManifest(name string) (*Manifest, error) {
+	v := viper.New()
+	v.SetConfigFile(name)
+	v.SetConfigType("toml")
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, errors.Wrapf(err, "could not read manifest '%s'", name)
+	}
+
+	var m Manifest
+	if err := v.Unmarshal(&m); err != nil {
+		return nil, errors.Wrapf(err, "could not decode manifest '%s'", name)
+	}
+
+	return &m, nil
+}
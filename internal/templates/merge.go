@@ -0,0 +1,64 @@
+// Package templates holds the template and project building functions
+package templates
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/bytemare/goproject/internal/diff"
+
+	"github.com/pkg/errors"
+)
+
+// Ways of leaving the conflicts of a merge : conflict markers in the file, or the rendered file written aside
+const (
+	ConflictMarkers = "markers"
+	ConflictFile    = "file"
+)
+
+const (
+	// baseDir holds, relative to the project, a copy of each file as it was last generated, the base of later merges.
+	// Bases are kept for files under the merge policy, and in projects that already have some.
+	baseDir = ".goproject/base"
+
+	// asideSuffix is appended to the name of a conflicting file to write the rendered file aside
+	asideSuffix = ".goproject-new"
+)
+
+// planMerge merges the rendered file into the existing one, taking the file as it was last generated as their base.
+// If the base is unknown, the lines both have in common are used instead. The file is skipped if it already holds
+// everything the rendered file brings.
+func (p *Project) planMerge(root string, f *FileAction) error {
+	base, err := p.fs().ReadFile(filepath.Join(root, baseDir, f.Path))
+
+	switch {
+	case err == nil:
+		f.Merged, f.Conflicts = diff.Merge(string(base), f.Existing, f.Content)
+	case os.IsNotExist(err):
+		f.Merged, f.Conflicts = diff.MergeWithoutBase(f.Existing, f.Content)
+	default:
+		return errors.Wrapf(err, "could not read base of file '%s'", f.Path)
+	}
+
+	if f.Merged == f.Existing {
+		return nil
+	}
+
+	f.Action = ActionMerge
+
+	if f.Conflicts != 0 && p.ConflictStyle == ConflictFile {
+		f.Aside = f.Path + asideSuffix
+	}
+
+	return nil
+}
+
+// writeBase keeps a copy of the rendered file, as the base of later merges, if bases are kept for the file
+func writeBase(t *transaction, f *FileAction) error {
+	if !f.keepBase {
+		return nil
+	}
+
+	return t.writeFile(path.Join(baseDir, f.Path), f.Content)
+}
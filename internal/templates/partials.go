@@ -0,0 +1,34 @@
+// Package templates holds the template and project building functions
+package templates
+
+// Identifiers of the built-in partials, to be called from templates with {{template "identifier" .}}
+const (
+	goVersionPartial      = "go-version"
+	golangciLintPartial   = "golangci-lint-version"
+	precommitHooksPartial = "pre-commit-hooks-version"
+	projectLinksPartial   = "project-links"
+)
+
+// builtinPartials returns the named snippets shared by the built-in templates, indexed by their identifier
+func builtinPartials() map[string]string {
+	return map[string]string{
+		goVersionPartial:      "1.14",
+		golangciLintPartial:   "v1.24.0",
+		precommitHooksPartial: "v2.5.0",
+		projectLinksPartial: `	- Project homepage: https://your.github.com/{{.Project.Name}}/
+	- Repository: https://github.com/your/{{.Project.Name}}/
+	- Issue tracker: https://github.com/your/{{.Project.Name}}/issues`,
+	}
+}
+
+// partials returns the partials available to the project's templates. Partials of the user, be they from the
+// partials directory or the profile, replace the built-in partials of the same name.
+func (p *Project) partials() map[string]string {
+	partials := builtinPartials()
+
+	for name, partial := range p.Profile.Partials {
+		partials[name] = partial
+	}
+
+	return partials
+}
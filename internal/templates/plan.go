@@ -0,0 +1,379 @@
+// Package templates holds the template and project building functions
+package templates
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bytemare/goproject/internal/diff"
+	"github.com/bytemare/goproject/internal/vfs"
+
+	"github.com/pkg/errors"
+)
+
+// Planned actions
+const (
+	ActionCreate    = "create"
+	ActionSkip      = "skip"
+	ActionMerge     = "merge"
+	ActionOverwrite = "overwrite"
+	ActionBackup    = "backup"
+	ActionPrompt    = "prompt"
+	ActionFail      = "fail"
+	ActionRun       = "run"
+	ActionError     = "error"
+)
+
+// Plan lists everything building the project does, without touching the disk
+type Plan struct {
+	// Root is the absolute path of the directory the project is built in, and CreateRoot whether it is to be created
+	Root       string
+	CreateRoot bool
+
+	// Module is the go module path of the project, and ModuleExists whether it is declared by an existing go.mod
+	Module       string
+	ModuleExists bool
+
+	Directories []*DirectoryAction
+	Files       []*FileAction
+	Commands    []*CommandAction
+}
+
+// DirectoryAction is a directory of the layout to create. If creating it failed, Err holds the error.
+type DirectoryAction struct {
+	Path   string
+	Action string
+	Err    error
+}
+
+// FileAction is a rendered file to write. If rendering the template failed, Path is empty and Err holds the error.
+type FileAction struct {
+	// Index is the position of the template in the resolved layout
+	Index   int
+	ID      string
+	Path    string
+	Content string
+	Action  string
+	Err     error
+
+	// Exists tells whether the file already exists, in which case Existing holds its content
+	Exists   bool
+	Existing string
+
+	// Merged is the result of merging the rendered file into the existing one, with Conflicts conflicting changes.
+	// If conflicts are not left as markers, the rendered file is instead written aside, to Aside.
+	Merged    string
+	Conflicts int
+	Aside     string
+
+	// Backup is where the existing file is moved before being overwritten
+	Backup string
+
+	// When updating, Pristine tells whether the existing file is as it was generated, and Modified if it is not
+	Pristine bool
+	Modified bool
+
+	file *file
+	tree bool
+
+	// keepBase tells whether to keep a copy of the rendered file as the base of later merges
+	keepBase bool
+}
+
+// CommandAction is a command to run in the project. If it failed, Err holds the error.
+type CommandAction struct {
+	Description string
+	Args        []string
+	Action      string
+	Reason      string
+	Err         error
+
+	// stage stages what the command created
+	stage func(t *transaction) error
+
+	// requires is the command this one depends on : it is skipped if that one failed
+	requires *CommandAction
+}
+
+// command returns the command line
+func (c *CommandAction) command() string {
+	return strings.Join(c.Args, " ")
+}
+
+// name returns the name of the file for display, telling apart the files of a template tree by their path
+func (f *FileAction) name() string {
+	if f.tree {
+		return fmt.Sprintf("%s (%s)", f.ID, f.Path)
+	}
+
+	return f.ID
+}
+
+// root returns the absolute path of the directory the project is built in, and whether it is to be created
+func (p *Project) root() (root string, create bool, err error) {
+	root, err = filepath.Abs(p.Path)
+	if err != nil {
+		return "", false, errors.Wrapf(err, "unable to get project directory")
+	}
+
+	e, err := vfs.Exists(p.fs(), root)
+	if err != nil {
+		return "", false, err
+	}
+
+	return root, !e, nil
+}
+
+// Plan resolves the layout and renders the templates, and returns what building the project would do
+func (p *Project) Plan() (*Plan, error) {
+	root, create, err := p.root()
+	if err != nil {
+		return nil, err
+	}
+
+	// The module of an existing go.mod is reused, as go modules are not initialised again
+	module, err := readModule(p.fs(), filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+
+	if module != "" {
+		p.Module = module
+	}
+
+	plan := &Plan{
+		Root:         root,
+		CreateRoot:   create,
+		Module:       p.Module,
+		ModuleExists: module != "",
+	}
+
+	dirs, files, err := p.resolveLayout(root)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range dirs {
+		action := &DirectoryAction{Path: d, Action: ActionCreate}
+
+		if e, _ := vfs.Exists(p.fs(), filepath.Join(root, d)); e {
+			action.Action = ActionSkip
+		}
+
+		plan.Directories = append(plan.Directories, action)
+	}
+
+	// Templates are rendered concurrently, and planned in the order of the layout
+	actions := make([][]*FileAction, len(files))
+	errs := make([]error, len(files))
+
+	parallel(len(files), p.workers(), func(i int) {
+		actions[i], errs[i] = p.planFile(root, i, files[i])
+	})
+
+	for i := range files {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+
+		plan.Files = append(plan.Files, actions[i]...)
+	}
+
+	plan.Commands = p.planCommands(root)
+
+	return plan, nil
+}
+
+// planFile renders the template of the layout entry, and returns the actions for each of its files
+func (p *Project) planFile(root string, index int, entry FileEntry) ([]*FileAction, error) {
+	fileID := entry.ID
+
+	policy, err := p.onConflict(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := p.buildFile(fileID)
+	if err != nil {
+		return []*FileAction{{Index: index, ID: fileID, Action: ActionError, Err: err}}, nil
+	}
+
+	// Bases are kept for files to merge, and in projects that already keep them
+	keepBase := policy == OnConflictMerge
+	if !keepBase {
+		keepBase, _ = vfs.Exists(p.fs(), filepath.Join(root, baseDir))
+	}
+
+	actions := make([]*FileAction, 0, len(files))
+
+	for _, f := range files {
+		action := &FileAction{
+			Index:    index,
+			ID:       fileID,
+			Path:     path.Join(f.directory, f.filename),
+			Content:  f.content,
+			Action:   ActionCreate,
+			file:     f,
+			tree:     len(files) > 1,
+			keepBase: keepBase,
+		}
+
+		e, err := vfs.Exists(p.fs(), filepath.Join(root, action.Path))
+		if err != nil {
+			action.Action = ActionError
+			action.Err = err
+		} else if e {
+			content, err := p.fs().ReadFile(filepath.Join(root, action.Path))
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not read existing file '%s'", action.Path)
+			}
+
+			action.Action = ActionSkip
+			action.Exists = true
+			action.Existing = string(content)
+
+			if err := p.planConflict(root, policy, action); err != nil {
+				return nil, err
+			}
+		}
+
+		actions = append(actions, action)
+	}
+
+	return actions, nil
+}
+
+// planCommands returns the commands initialising go modules and git
+func (p *Project) planCommands(root string) []*CommandAction {
+	goMod := &CommandAction{
+		Description: "Initialising go modules.",
+		Args:        []string{"go", "mod", "init", p.Module},
+		Action:      ActionRun,
+		stage: func(t *transaction) error {
+			t.journal.GoModInit = true
+
+			content, err := t.fs.ReadFile(filepath.Join(t.staging, "go.mod"))
+			if err != nil {
+				return err
+			}
+
+			return t.stage("go.mod", string(content))
+		},
+	}
+
+	if e, _ := vfs.Exists(p.fs(), filepath.Join(root, "go.mod")); e {
+		goMod.Action = ActionSkip
+		goMod.Reason = "Go module (go.mod) already exists. Skipping initialisation."
+	}
+
+	return append([]*CommandAction{goMod}, p.planGit(root)...)
+}
+
+// Print writes a human readable version of the plan. Rendered contents of the files to create, and unified diffs
+// between the existing and rendered files, are optional.
+func (pl *Plan) Print(w io.Writer, showContent, showDiff bool) {
+	if pl.CreateRoot {
+		fmt.Fprintf(w, "Project directory %s will be created.\n", pl.Root)
+	} else {
+		fmt.Fprintf(w, "Project will be built in %s.\n", pl.Root)
+	}
+
+	if pl.ModuleExists {
+		fmt.Fprintf(w, "Module path %s (from the existing go.mod).\n", pl.Module)
+	} else {
+		fmt.Fprintf(w, "Module path %s.\n", pl.Module)
+	}
+
+	if len(pl.Directories) != 0 {
+		fmt.Fprintln(w, "Directories :")
+
+		for _, d := range pl.Directories {
+			if d.Action == ActionSkip {
+				fmt.Fprintf(w, "\t= %s (already exists)\n", d.Path)
+			} else {
+				fmt.Fprintf(w, "\t+ %s\n", d.Path)
+			}
+		}
+	}
+
+	if len(pl.Files) != 0 {
+		fmt.Fprintln(w, "Files :")
+
+		for _, f := range pl.Files {
+			pl.printFile(w, f, showContent, showDiff)
+		}
+	}
+
+	fmt.Fprintln(w, "Commands :")
+
+	for _, c := range pl.Commands {
+		if c.Action == ActionSkip {
+			fmt.Fprintf(w, "\t- %s (%s)\n", c.command(), c.Reason)
+		} else {
+			fmt.Fprintf(w, "\t$ %s\n", c.command())
+		}
+	}
+}
+
+func (pl *Plan) printFile(w io.Writer, f *FileAction, showContent, showDiff bool) {
+	switch f.Action {
+	case ActionError:
+		fmt.Fprintf(w, "\t! %s : error : %v\n", f.name(), f.Err)
+		return
+	case ActionSkip:
+		if f.Modified {
+			fmt.Fprintf(w, "\t= %s [%s] (modified since it was generated, skipped)\n", f.Path, f.ID)
+		} else {
+			fmt.Fprintf(w, "\t= %s [%s] (already exists, skipped)\n", f.Path, f.ID)
+		}
+	case ActionOverwrite:
+		if f.Pristine {
+			fmt.Fprintf(w, "\t> %s [%s] (unmodified since it was generated, updated)\n", f.Path, f.ID)
+			break
+		}
+
+		fmt.Fprintf(w, "\t> %s [%s] (already exists, overwritten)\n", f.Path, f.ID)
+	case ActionBackup:
+		fmt.Fprintf(w, "\t> %s [%s] (already exists, backed up to %s and overwritten)\n", f.Path, f.ID, f.Backup)
+	case ActionPrompt:
+		fmt.Fprintf(w, "\t? %s [%s] (already exists, asking before overwriting)\n", f.Path, f.ID)
+	case ActionFail:
+		fmt.Fprintf(w, "\t! %s [%s] (already exists, generation aborts)\n", f.Path, f.ID)
+	case ActionMerge:
+		switch {
+		case f.Aside != "":
+			fmt.Fprintf(w, "\t~ %s [%s] (%d conflicts, rendered file written to %s)\n", f.Path, f.ID, f.Conflicts, f.Aside)
+		case f.Conflicts != 0:
+			fmt.Fprintf(w, "\t~ %s [%s] (merge, %d conflicts)\n", f.Path, f.ID, f.Conflicts)
+		default:
+			fmt.Fprintf(w, "\t~ %s [%s] (merge)\n", f.Path, f.ID)
+		}
+	default:
+		fmt.Fprintf(w, "\t+ %s [%s]\n", f.Path, f.ID)
+	}
+
+	switch {
+	case showDiff:
+		from := "/dev/null"
+		if f.Exists {
+			from = "a/" + f.Path
+		}
+
+		// Merges show the changes to the existing file
+		to := f.Content
+		if f.Action == ActionMerge && f.Aside == "" {
+			to = f.Merged
+		}
+
+		fmt.Fprint(w, diff.Unified(from, "b/"+f.Path, f.Existing, to, 3))
+	case showContent && f.Action == ActionCreate:
+		fmt.Fprint(w, f.Content)
+
+		if !strings.HasSuffix(f.Content, "\n") {
+			fmt.Fprintln(w)
+		}
+	}
+}
@@ -4,9 +4,9 @@ package templates
 const precommitIdentifier = "pre-commit"
 
 // makefileConstructor returns the file content populated with the relevant values
-func precommitConstructor(project *Project) (*file, error) { //nolint:unparam // project is not needed when no variables
+func precommitConstructor(project *Project) (*file, error) {
 	f, d, t := precommitValues()
-	return newProjectFile(newFile(precommitIdentifier, f, d, t))
+	return newProjectFile(project, newFile(precommitIdentifier, f, d, t))
 }
 
 func precommitValues() (f, d, t string) {
@@ -16,7 +16,7 @@ func precommitValues() (f, d, t string) {
 
 	const template = `repos:
   -   repo: https://github.com/pre-commit/pre-commit-hooks
-      rev: v2.5.0
+      rev: {{template "pre-commit-hooks-version"}}
       hooks:
         -   id: trailing-whitespace
         -   id: check-docstring-first
@@ -44,7 +44,7 @@ func precommitValues() (f, d, t string) {
     hooks:
       - id: sign-commit
   - repo: https://github.com/golangci/golangci-lint
-    rev: v1.24.0
+    rev: {{template "golangci-lint-version"}}
     hooks:
       - id: golangci-lint
   - repo: local
@@ -2,15 +2,22 @@
 package templates
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/bytemare/goproject/internal/config"
+	"github.com/bytemare/goproject/internal/vfs"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
+	"github.com/spf13/viper"
 )
 
 // Project structure contains all the necessary information regarding a Project,
@@ -18,126 +25,341 @@ import (
 type Project struct {
 	Profile *config.Profile
 	Name    string
+	Module  string
 	Path    string
 	Layout  layout
 	Author  *config.Author
-}
+	Vars    map[string]interface{}
+
+	// OnConflict is the policy for rendered files that already exist, and ConflictStyle how merges leave conflicts
+	OnConflict    string `mapstructure:"-"`
+	ConflictStyle string `mapstructure:"-"`
+
+	// Confirm asks a yes/no question, to know whether to overwrite the files under the prompt policy
+	Confirm func(question string) (bool, error) `mapstructure:"-"`
+
+	// KeepGoing tells to write the steps that succeeded even if others failed
+	KeepGoing bool `mapstructure:"-"`
+
+	// Jobs is the number of templates rendered and written concurrently, the number of CPUs if not set
+	Jobs int `mapstructure:"-"`
+
+	// Previous is the manifest of the previous generation, when updating the project. Files left as they were
+	// generated are then updated, and the policy only applies to modified files.
+	Previous *Manifest `mapstructure:"-"`
+
+	// profileVars are the variables of the profile, before the given ones replaced them
+	profileVars map[string]interface{}
+
+	// Out receives the progress of the build, the standard output if nil
+	Out io.Writer `mapstructure:"-"`
 
-// layout represents the directory and file layout of the Project
-type layout struct {
-	Directories []string
-	Files       []string
+	// FS is the file system the project is read from and built into, the disk if nil
+	FS vfs.FS `mapstructure:"-"`
 }
 
 // NewProject returns a new Project structure given a name, where it is to be created,
-// and a profile containing the directives for the Project layout
-func NewProject(prof *config.Profile, name, location string) *Project {
+// and a profile containing the directives for the Project layout.
+// The given variables are added to the ones of the profile, replacing those of the same name.
+func NewProject(prof *config.Profile, name, location string, vars map[string]interface{}) (*Project, error) {
+	if prof.Conf == nil {
+		return nil, errors.Errorf("profile '%s' has no configuration", prof.Name)
+	}
+
 	project := &Project{
 		Profile: prof,
 		Name:    name,
 		Path:    filepath.Join(location, name),
 		Layout: layout{
-			Directories: make([]string, 0),
-			Files:       make([]string, 0),
+			Directories: make([]DirectoryEntry, 0),
+			Files:       make([]FileEntry, 0),
 		},
 		Author: prof.Author,
+		Vars:   make(map[string]interface{}),
 	}
 
-	err := prof.Conf.Unmarshal(project)
+	err := prof.Conf.Unmarshal(project, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		layoutEntryHook,
+		// viper's default hooks
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	)))
 	if err != nil {
-		fmt.Printf("unable to decode into struct, %v", err)
+		return nil, errors.Wrapf(err, "unable to decode profile '%s'", prof.Name)
+	}
+
+	project.profileVars = make(map[string]interface{}, len(project.Vars))
+	for k, v := range project.Vars {
+		project.profileVars[k] = v
+	}
+
+	// Variable names are case insensitive, as are the profile's keys
+	for k, v := range vars {
+		project.Vars[strings.ToLower(k)] = v
+	}
+
+	if prof.Git != nil {
+		project.Module = modulePath(prof.Git.URL, name)
+	} else {
+		project.Module = name
 	}
 
-	return project
+	return project, nil
 }
 
-// Build creates the Project structure and writes files
-func (p *Project) Build() error {
-	// If we are already in a directory named after the project, don't create it again
-	wd, err := os.Getwd()
+// Build creates the Project structure and writes files, and returns the report of the build.
+// If a step fails, nothing is written and the returned BuildError lists all failed steps, unless the project is to keep
+// going, in which case the steps that succeeded are written nonetheless.
+func (p *Project) Build() (*Report, error) {
+	plan, err := p.Plan()
 	if err != nil {
-		return errors.Wrapf(err, "unable to get working directory")
+		return nil, err
 	}
 
-	if path.Base(wd) != p.Name {
-		// Create Project destination folder if it does not exist already
-		if err := os.MkdirAll(p.Path, config.DirMode); err != nil {
-			return err
-		}
+	return p.BuildPlan(context.Background(), plan)
+}
 
-		if err := os.Chdir(p.Path); err != nil {
-			return errors.Wrapf(err, "could not build Project in '%s'", p.Path)
-		}
+// BuildPlan builds the plan returned by Plan, and returns the report of the build, as Build does.
+// If the context is done before the project is moved into place, nothing is written.
+func (p *Project) BuildPlan(ctx context.Context, plan *Plan) (*Report, error) {
+	written, err := p.build(ctx, plan)
+
+	return plan.report(p.Name, written, err), err
+}
+
+// fs returns the file system of the project
+func (p *Project) fs() vfs.FS {
+	if p.FS == nil {
+		return vfs.OS{}
+	}
+
+	return p.FS
+}
+
+// out returns where to write the progress of the build
+func (p *Project) out() io.Writer {
+	if p.Out == nil {
+		return os.Stdout
+	}
+
+	return p.Out
+}
+
+// build builds the plan, and returns whether the project was written
+func (p *Project) build(ctx context.Context, plan *Plan) (bool, error) {
+	// Nothing is written if a file is not to be touched, and all questions are asked beforehand
+	if err := plan.check(); err != nil {
+		return false, err
+	}
+
+	if err := p.prompt(plan); err != nil {
+		return false, err
+	}
+
+	// Everything is staged first, and only moved into the project if all steps succeeded
+	t, err := newTransaction(p.fs(), plan.Root, p.out())
+	if err != nil {
+		return false, err
+	}
+	defer t.close()
+
+	if plan.CreateRoot {
+		t.journal.Root = t.target
 	}
 
 	// Build the directory and file layout
-	p.buildDirs()
-	p.buildFiles()
+	var failed failures
+
+	buildDirs(t, plan.Directories, &failed)
+	buildFiles(t, plan.Files, &failed, p.workers())
+
+	if err := ctx.Err(); err != nil {
+		return false, errors.Wrap(err, "nothing was written")
+	}
+
+	if len(failed) == 0 || p.KeepGoing {
+		if err := p.record(ctx, t, plan, &failed); err != nil {
+			return false, errors.Wrap(err, "nothing was written")
+		}
+	} else {
+		skipCommands(plan.Commands)
+	}
+
+	if len(failed) != 0 && !p.KeepGoing {
+		return false, failed.err(false)
+	}
+
+	// Past this point, the generation is not interrupted anymore
+	if err := ctx.Err(); err != nil {
+		return false, errors.Wrap(err, "nothing was written")
+	}
+
+	// On other file systems, e.g. in memory for archives, the target is not a path of the disk
+	if _, ok := t.fs.(vfs.OS); ok {
+		fmt.Fprintf(t.out, "Moving project into %s.\n", t.target)
+	}
+
+	if err := t.commit(); err != nil {
+		return false, err
+	}
 
-	// Initialise git and go modules
-	if err := goMod(); err != nil {
+	return true, failed.err(true)
+}
+
+// record records the generation in the manifest, initialises git and go modules, and records what the generation
+// creates in the journal
+func (p *Project) record(ctx context.Context, t *transaction, plan *Plan, failed *failures) error {
+	fmt.Fprintf(t.out, "Recording generation in %s.\n", ManifestName)
+
+	manifest, err := p.manifest(plan).encode()
+	if err != nil {
 		return err
 	}
 
-	if err := gitInit(); err != nil {
+	if err := t.writeFile(ManifestName, manifest); err != nil {
+		return errors.Wrap(err, "could not write manifest")
+	}
+
+	runCommands(ctx, t, plan.Commands, failed, p.KeepGoing)
+
+	// The journal records its own directory, which is encoded with it
+	if err := t.mkdir(path.Dir(journalName)); err != nil {
+		return errors.Wrap(err, "could not write journal")
+	}
+
+	journal, err := t.journal.encode()
+	if err != nil {
 		return err
 	}
 
-	return nil
+	return errors.Wrap(t.writeFile(journalName, journal), "could not write journal")
 }
 
 const buildErrFormat = "error : %v\n"
 
-func (p *Project) buildDirs() {
-	if len(p.Layout.Directories) == 0 {
+func buildDirs(t *transaction, dirs []*DirectoryAction, failed *failures) {
+	if len(dirs) == 0 {
 		return
 	}
 
-	fmt.Println("Creating directory layout.")
+	fmt.Fprintln(t.out, "Creating directory layout.")
 
-	for i, d := range p.Layout.Directories {
-		fmt.Printf("\t> %d : Building directory %s ... ", i, d)
+	for i, d := range dirs {
+		fmt.Fprintf(t.out, "\t> %d : Building directory %s ... ", i, d.Path)
 
-		if err := os.MkdirAll(d, config.DirMode); err != nil {
-			fmt.Printf(buildErrFormat, err)
+		if err := t.mkdir(d.Path); err != nil {
+			d.Action = ActionError
+			d.Err = err
+
+			fmt.Fprintf(t.out, buildErrFormat, err)
+			failed.add(StepDirectory, d.Path, err)
+		} else if d.Action == ActionSkip {
+			fmt.Fprintln(t.out, "already exists. Skipping.")
 		} else {
-			fmt.Printf("success.\n")
+			fmt.Fprintf(t.out, "success.\n")
 		}
 	}
 }
 
-func (p *Project) buildFiles() {
-	if len(p.Layout.Files) == 0 {
+func buildFiles(t *transaction, files []*FileAction, failed *failures, workers int) {
+	if len(files) == 0 {
 		return
 	}
 
-	fmt.Println("Creating files.")
+	fmt.Fprintln(t.out, "Creating files.")
 
-	for i, fid := range p.Layout.Files {
-		fmt.Printf("\t> %d : Build file %s ... ", i, fid)
+	// Files are written concurrently, and reported in order
+	statuses := make([]string, len(files))
+	errs := make([]error, len(files))
 
-		if f, err := p.buildFile(fid); err != nil {
-			fmt.Printf(buildErrFormat, err)
-		} else {
-			e, err := exists(path.Join(f.directory, f.filename))
-			if e {
-				if err == nil {
-					fmt.Println("already exists. Skipping.")
-				}
-				continue
-			}
+	parallel(len(files), workers, func(i int) {
+		if files[i].Action != ActionError {
+			statuses[i], errs[i] = writeAction(t, files[i])
+		}
+	})
 
-			if err := f.write(); err != nil {
-				fmt.Printf(buildErrFormat, err)
-			}
-			fmt.Printf("success.\n")
+	t.sort()
+
+	for i, f := range files {
+		fmt.Fprintf(t.out, "\t> %d : Build file %s ... ", f.Index, f.name())
+
+		if f.Action == ActionError {
+			fmt.Fprintf(t.out, buildErrFormat, f.Err)
+			failed.add(StepFile, f.name(), f.Err)
+
+			continue
+		}
+
+		if err := errs[i]; err != nil {
+			// The file is not part of the generation anymore
+			f.Action = ActionError
+			f.Err = err
+
+			fmt.Fprintf(t.out, buildErrFormat, err)
+			failed.add(StepFile, f.name(), err)
+
+			continue
+		}
+
+		fmt.Fprintln(t.out, statuses[i])
+	}
+}
+
+// writeAction writes the file as planned, and returns the status to display
+func writeAction(t *transaction, f *FileAction) (string, error) {
+	switch f.Action {
+	case ActionSkip:
+		// An identical file can serve as the base of later merges
+		if f.Existing == f.Content {
+			_ = writeBase(t, f)
+		}
+
+		if f.Modified {
+			return "modified since it was generated. Skipping.", nil
+		}
+
+		return "already exists. Skipping.", nil
+	case ActionMerge:
+		return mergeFile(t, f)
+	case ActionOverwrite, ActionBackup:
+		return overwriteFile(t, f)
+	default:
+		if err := t.writeFile(f.Path, f.Content); err != nil {
+			return "", err
 		}
+
+		return "success.", writeBase(t, f)
+	}
+}
+
+// mergeFile writes the merged file, or the rendered file aside if conflicts are not to be left as markers
+func mergeFile(t *transaction, f *FileAction) (string, error) {
+	if f.Aside != "" {
+		if err := t.writeFile(f.Aside, f.Content); err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%d conflicts. Rendered file written to %s.", f.Conflicts, f.Aside), nil
+	}
+
+	if err := t.writeFile(f.Path, f.Merged); err != nil {
+		return "", err
+	}
+
+	if err := writeBase(t, f); err != nil {
+		return "", err
+	}
+
+	if f.Conflicts != 0 {
+		return fmt.Sprintf("merged with %d conflicts.", f.Conflicts), nil
 	}
+
+	return "merged.", nil
 }
 
-func (p *Project) buildFile(fileID string) (*file, error) {
+func (p *Project) buildFile(fileID string) ([]*file, error) {
 	// fetch the constructor for corresponding file identifier
-	constructor, err := getFileConstructor(fileID)
+	constructor, err := p.getFileConstructor(fileID)
 	if err != nil {
 		return nil, err
 	}
@@ -146,41 +368,84 @@ func (p *Project) buildFile(fileID string) (*file, error) {
 	return constructor(p)
 }
 
-func gitInit() error {
-	fmt.Println("Initialising git.")
+// runCommands runs the commands in the staging directory. Unless keepGoing is set, it stops at the first failure.
+func runCommands(ctx context.Context, t *transaction, commands []*CommandAction, failed *failures, keepGoing bool) {
+	for i, c := range commands {
+		fmt.Fprintln(t.out, c.Description)
 
-	e, err := exists(".git")
-	if e {
-		if err == nil {
-			fmt.Println("\tGit directory (.git) already exists. Skipping initialisation.")
+		if c.Action == ActionSkip {
+			fmt.Fprintf(t.out, "\t%s\n", c.Reason)
+			continue
 		}
 
-		return err
-	}
+		if c.requires != nil && c.requires.Action == ActionError {
+			c.Action = ActionSkip
+			c.Reason = "A previous step failed."
+			fmt.Fprintf(t.out, "\t%s\n", c.Reason)
 
-	cmd := exec.Command("git", "init")
+			continue
+		}
 
-	return cmd.Run()
-}
+		if err := runCommand(ctx, t, c); err != nil {
+			c.Action = ActionError
+			c.Err = err
 
-func goMod() error {
-	fmt.Println("Initialising go modules.")
+			fmt.Fprintf(t.out, "\t"+buildErrFormat, err)
+			failed.add(StepCommand, c.command(), err)
 
-	cmd := exec.Command("go", "mod", "init")
+			if !keepGoing {
+				skipCommands(commands[i+1:])
+				return
+			}
+		}
+	}
+}
 
-	return cmd.Run()
+// skipCommands marks the commands as skipped, as a previous step failed
+func skipCommands(commands []*CommandAction) {
+	for _, c := range commands {
+		if c.Action == ActionRun {
+			c.Action = ActionSkip
+			c.Reason = "A previous step failed."
+		}
+	}
 }
 
-// exists returns whether the given file or directory exists
-func exists(location string) (bool, error) {
-	_, err := os.Stat(location)
-	if err == nil {
-		return true, nil
+func runCommand(ctx context.Context, t *transaction, c *CommandAction) error {
+	dir := t.staging
+
+	// Commands run on the disk : on another file system, they run on a copy of the staging directory
+	if _, ok := t.fs.(vfs.OS); !ok {
+		tmp, err := ioutil.TempDir("", "goproject-")
+		if err != nil {
+			return errors.Wrap(err, "could not create command directory")
+		}
+		defer os.RemoveAll(tmp)
+
+		if err := vfs.Copy(vfs.OS{}, tmp, t.fs, t.staging); err != nil {
+			return err
+		}
+
+		dir = tmp
 	}
 
-	if os.IsNotExist(err) {
-		return false, nil
+	cmd := exec.CommandContext(ctx, c.Args[0], c.Args[1:]...) //nolint:gosec // commands are set by goproject
+	cmd.Dir = dir
+
+	// The output of a command tells why it failed
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return errors.Errorf("%v : %s", err, msg)
+		}
+
+		return err
+	}
+
+	if dir != t.staging {
+		if err := vfs.Copy(t.fs, t.staging, vfs.OS{}, dir); err != nil {
+			return err
+		}
 	}
 
-	return true, err
+	return c.stage(t)
 }
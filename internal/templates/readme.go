@@ -3,38 +3,10 @@ package templates
 
 const readmeIdentifier = "readme"
 
-// readme implements the fileTemplate interface for a Readme
-type readme struct {
-	*file
-	ProjectName string
-	CI          *interface{}
-}
-
 // readmeConstructor returns the file content populated with the relevant values
 func readmeConstructor(project *Project) (*file, error) {
-	return newProjectFile(newReadme(project.Name))
-}
-
-func newReadme(projectName string) *readme {
 	f, d, t := readmeValues()
-
-	return &readme{
-		file:        newFile(readmeIdentifier, f, d, t),
-		ProjectName: projectName,
-		CI:          nil,
-	}
-}
-
-func (r *readme) getIdentifier() string {
-	return r.identifier
-}
-
-func (r *readme) getFilename() string {
-	return r.filename
-}
-
-func (r *readme) getTemplate() string {
-	return r.template
+	return newProjectFile(project, newFile(readmeIdentifier, f, d, t))
 }
 
 func readmeValues() (f, d, t string) { //nolint:funlen // length is due to a constant, no complexity here
@@ -45,13 +17,11 @@ func readmeValues() (f, d, t string) { //nolint:funlen // length is due to a con
 	const template = `
 [Template inspired by Jesse Luoto // https://github.com/jehna/readme-best-practices/blob/master/README-default.md]
 
-# {{.ProjectName}}
+# {{.Project.Name}}
 ![Logo of the Project](https://raw.githubusercontent.com/jehna/readme-best-practices/master/sample-logo.png)
 
-  {{if .CI -}}
-      {{- range .CI}}
-  {{. -}}
-      {{end}}
+  {{with .CI.URL -}}
+  {{.}}
   {{end}}
 > Additional information or tagline
 
@@ -64,9 +34,9 @@ A quick introduction of the minimal setup you need to get a hello world up &
 running.
 
 \'\'\'shell
-	packagemanager install {{.ProjectName}}
-	{{.ProjectName}} start
-	{{.ProjectName}} "Do something!"  # prints "Nah."
+	packagemanager install {{.Project.Name}}
+	{{.Project.Name}} start
+	{{.Project.Name}} "Do something!"  # prints "Nah."
 	\'\'\'
 
 Here you should say what actually happens when you execute the code above.
@@ -82,8 +52,8 @@ Here's a brief intro about what a developer must do in order to start developing
 the Project further:
 
 \'\'\'shell
-	git clone https://github.com/your/{{.ProjectName}}.git
-	cd {{.ProjectName}}/
+	git clone https://github.com/your/{{.Project.Name}}.git
+	cd {{.Project.Name}}/
 		packagemanager install
 	\'\'\'
 
@@ -109,7 +79,7 @@ In case there's some step you have to take that publishes this Project to a
 server, this is the right time to state it.
 
 \'\'\'shell
-	packagemanager deploy {{.ProjectName}} -s server.com -u username -p password
+	packagemanager deploy {{.Project.Name}} -s server.com -u username -p password
 	\'\'\'
 
 And again you'd need to tell what the previous code actually does.
@@ -135,7 +105,7 @@ Default: \''default value'\'
 
 		Example:
 	\'\'\'bash
-{{.ProjectName}} "Some other value"  # Prints "You're nailing this readme!"
+{{.Project.Name}} "Some other value"  # Prints "You're nailing this readme!"
 \'\'\'
 
 	#### Argument 2
@@ -176,15 +146,13 @@ Default: 100
 	format like in a .json file, it's good to include a summary of most useful
 	links to humans using your Project. You can include links like:
 
-	- Project homepage: https://your.github.com/{{.ProjectName}}/
-	- Repository: https://github.com/your/{{.ProjectName}}/
-	- Issue tracker: https://github.com/your/{{.ProjectName}}/issues
+{{template "project-links" .}}
 	- In case of sensitive bugs like security vulnerabilities, please contact
 my@email.com directly instead of using issue tracker. We value your effort
 to improve the security and privacy of this Project!
 - Related projects:
 - Your other Project: https://github.com/your/other-Project/
-- Someone else's Project: https://github.com/someones/{{.ProjectName}}/
+- Someone else's Project: https://github.com/someones/{{.Project.Name}}/
 
 ## Licensing
 
@@ -0,0 +1,135 @@
+// Package templates holds the template and project building functions
+package templates
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/bytemare/goproject/internal/config"
+)
+
+// Sources of the registered templates
+const (
+	SourceBuiltin  = "built-in"
+	SourceUser     = "user"
+	SourceReplaced = "user, replaces built-in"
+)
+
+// TemplateInfo describes a registered template
+type TemplateInfo struct {
+	ID     string
+	Source string
+	Files  []*RawFile
+}
+
+// RawFile is a file of a template before rendering. Its path, relative to the project, may itself be a template.
+type RawFile struct {
+	Path     string
+	Template string
+}
+
+// RenderedFile is a file rendered from a template. Its path is relative to the project.
+type RenderedFile struct {
+	Path    string
+	Content string
+}
+
+// builtinValues returns the filename, directory and raw template of the built-in templates, indexed by their identifier
+func builtinValues() map[string]func() (f, d, t string) {
+	return map[string]func() (f, d, t string){
+		docIdentifier:        docValues,
+		dockerfileIdentifier: dockerfileValues,
+		gitignoreIdentifier:  gitignoreValues,
+		golangciIdentifier:   golangciValues,
+		makefileIdentifier:   makefileValues,
+		precommitIdentifier:  precommitValues,
+		readmeIdentifier:     readmeValues,
+		sonarIdentifier:      sonarValues,
+		travisIdentifier:     travisValues,
+		versionIdentifier:    versionValues,
+	}
+}
+
+// userRawFiles returns the raw files of a user template
+func userRawFiles(t *config.Template) []*RawFile {
+	files := make([]*RawFile, 0, len(t.Files)+1)
+
+	if t.Filename != "" {
+		files = append(files, &RawFile{Path: path.Join(t.Directory, t.Filename), Template: t.Template})
+	}
+
+	for _, f := range t.Files {
+		files = append(files, &RawFile{Path: f.Path, Template: f.Template})
+	}
+
+	return files
+}
+
+// ListTemplates returns the built-in templates and the user templates registered for the profile,
+// sorted by identifier
+func ListTemplates(prof *config.Profile) []*TemplateInfo {
+	res := make([]*TemplateInfo, 0, len(builtinValues())+len(prof.Templates))
+
+	for id, values := range builtinValues() {
+		if _, ok := prof.Templates[id]; ok {
+			continue
+		}
+
+		f, d, t := values()
+		res = append(res, &TemplateInfo{
+			ID:     id,
+			Source: SourceBuiltin,
+			Files:  []*RawFile{{Path: path.Join(d, f), Template: t}},
+		})
+	}
+
+	for id, t := range prof.Templates {
+		source := SourceUser
+		if _, ok := builtinValues()[id]; ok {
+			source = SourceReplaced
+		}
+
+		res = append(res, &TemplateInfo{
+			ID:     id,
+			Source: source,
+			Files:  userRawFiles(t),
+		})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID < res[j].ID
+	})
+
+	return res
+}
+
+// GetTemplate returns the template registered for the profile under the given identifier
+func GetTemplate(prof *config.Profile, id string) (*TemplateInfo, error) {
+	for _, t := range ListTemplates(prof) {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("error : '%s' is not a registered Project file", id)
+}
+
+// Render renders the template registered under the given identifier for the project, whether it is part of the
+// project's layout or not, and without writing anything
+func (p *Project) Render(id string) ([]*RenderedFile, error) {
+	files, err := p.buildFile(id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*RenderedFile, len(files))
+	for i, f := range files {
+		res[i] = &RenderedFile{
+			Path:    path.Join(f.directory, f.filename),
+			Content: f.content,
+		}
+	}
+
+	return res, nil
+}
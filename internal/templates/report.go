@@ -0,0 +1,157 @@
+// Package templates holds the template and project building functions
+package templates
+
+// Statuses of the steps of a build in its report
+const (
+	StatusCreated     = "created"
+	StatusOverwritten = "overwritten"
+	StatusMerged      = "merged"
+	StatusSkipped     = "skipped"
+	StatusRan         = "ran"
+	StatusFailed      = "failed"
+
+	// StatusNotWritten is the status of the steps that succeeded in a build that failed, and was not written
+	StatusNotWritten = "not-written"
+)
+
+// Report is the outcome of a build : one record per directory, file and command. Written tells whether the project
+// was written, and Error holds the error of the build, if any.
+type Report struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	Written bool      `json:"written"`
+	Error   string    `json:"error,omitempty"`
+	Records []*Record `json:"records"`
+}
+
+// Record is the outcome of a step of a build. Type is one of the build steps, and Status one of the statuses.
+// Path is relative to the project : commands run in its directory, '.', and files that could not be rendered have the
+// identifier of their template instead. The hash is the sha256 hash of the content of the file in the project after
+// the build.
+type Record struct {
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	Path      string `json:"path,omitempty"`
+	Template  string `json:"template,omitempty"`
+	Hash      string `json:"sha256,omitempty"`
+	Command   string `json:"command,omitempty"`
+	Backup    string `json:"backup,omitempty"`
+	Aside     string `json:"aside,omitempty"`
+	Conflicts int    `json:"conflicts,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report returns the report of what building the plan would do. Nothing is written yet.
+func (pl *Plan) Report(name string) *Report {
+	return pl.report(name, false, nil)
+}
+
+// report returns the report of the build of the plan
+func (pl *Plan) report(name string, written bool, err error) *Report {
+	r := &Report{
+		Name:    name,
+		Path:    pl.Root,
+		Written: written,
+		Records: make([]*Record, 0, len(pl.Directories)+len(pl.Files)+len(pl.Commands)),
+	}
+
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	for _, d := range pl.Directories {
+		r.Records = append(r.Records, d.record())
+	}
+
+	for _, f := range pl.Files {
+		r.Records = append(r.Records, f.record())
+	}
+
+	for _, c := range pl.Commands {
+		r.Records = append(r.Records, c.record())
+	}
+
+	// Nothing of a failed build is in the project
+	if err != nil && !written {
+		for _, rec := range r.Records {
+			switch rec.Status {
+			case StatusSkipped, StatusFailed:
+			default:
+				rec.Status = StatusNotWritten
+				rec.Hash = ""
+			}
+		}
+	}
+
+	return r
+}
+
+func (d *DirectoryAction) record() *Record {
+	r := &Record{Type: StepDirectory, Path: d.Path}
+
+	switch d.Action {
+	case ActionSkip:
+		r.Status = StatusSkipped
+	case ActionError:
+		r.Status = StatusFailed
+		r.Error = d.Err.Error()
+	default:
+		r.Status = StatusCreated
+	}
+
+	return r
+}
+
+func (f *FileAction) record() *Record {
+	r := &Record{Type: StepFile, Path: f.Path, Template: f.ID}
+
+	// Without a path, the file is known by its template
+	if r.Path == "" {
+		r.Path = f.ID
+	}
+
+	switch f.Action {
+	case ActionError:
+		r.Status = StatusFailed
+		r.Error = f.Err.Error()
+	case ActionSkip:
+		r.Status = StatusSkipped
+		r.Hash = hash(f.Existing)
+	case ActionOverwrite, ActionBackup:
+		r.Status = StatusOverwritten
+		r.Hash = hash(f.Content)
+		r.Backup = f.Backup
+	case ActionMerge:
+		r.Status = StatusMerged
+		r.Conflicts = f.Conflicts
+		r.Aside = f.Aside
+
+		// With conflicts written aside, the file is left untouched
+		if f.Aside != "" {
+			r.Hash = hash(f.Existing)
+		} else {
+			r.Hash = hash(f.Merged)
+		}
+	default:
+		r.Status = StatusCreated
+		r.Hash = hash(f.Content)
+	}
+
+	return r
+}
+
+func (c *CommandAction) record() *Record {
+	r := &Record{Type: StepCommand, Path: ".", Command: c.command()}
+
+	switch c.Action {
+	case ActionSkip:
+		r.Status = StatusSkipped
+	case ActionError:
+		r.Status = StatusFailed
+		r.Error = c.Err.Error()
+	default:
+		r.Status = StatusRan
+	}
+
+	return r
+}
@@ -3,47 +3,10 @@ package templates
 
 const sonarIdentifier = "sonar"
 
-type sonar struct {
-	*file
-	SonarOrg     string
-	SonarProject string
-	RepoCI       string
-	RepoURL      string
-}
-
 // sonarConstructor returns the file content populated with the relevant values
 func sonarConstructor(project *Project) (*file, error) {
-	conf := project.Profile.Conf
-	sonarOrg := conf.GetString("sonar.org")
-	sonarProject := conf.GetString("name")
-	repoCI := conf.GetString("travis.profile")
-	repoURL := conf.GetString("git.URL")
-
-	return newProjectFile(newSonar(sonarOrg, sonarProject, repoCI, repoURL))
-}
-
-func newSonar(sonarOrg, sonarProject, repoCI, repoURL string) *sonar {
 	f, d, t := sonarValues()
-
-	return &sonar{
-		file:         newFile(sonarIdentifier, f, d, t),
-		SonarOrg:     sonarOrg,
-		SonarProject: sonarProject,
-		RepoCI:       repoCI,
-		RepoURL:      repoURL,
-	}
-}
-
-func (s *sonar) getIdentifier() string {
-	return s.identifier
-}
-
-func (s *sonar) getFilename() string {
-	return s.filename
-}
-
-func (s *sonar) getTemplate() string {
-	return s.template
+	return newProjectFile(project, newFile(sonarIdentifier, f, d, t))
 }
 
 func sonarValues() (f, d, t string) {
@@ -52,15 +15,15 @@ func sonarValues() (f, d, t string) {
 	const directory = "."
 
 	const template = `# Project identification
-sonar.organization={{.SonarOrg}}
-sonar.projectKey={{.SonarProject}}
-sonar.projectName={{.SonarProject}}
+sonar.organization={{.Sonar.Org}}
+sonar.projectKey={{.Project.Name}}
+sonar.projectName={{.Project.Name}}
 #sonar.projectVersion=1
 
 # Project Metadata
-sonar.links.ci={{.RepoCI}}
-sonar.links.homepage={{.RepoURL}}
-sonar.links.scm={{.RepoURL}}
+sonar.links.ci={{.CI.URL}}
+sonar.links.homepage={{.Git.URL}}
+sonar.links.scm={{.Git.URL}}
 sonar.host.url=https://sonarcloud.io
 
 # Project files
@@ -4,13 +4,9 @@ package templates
 import (
 	"bytes"
 	"fmt"
-	"io/ioutil"
-	"path"
 	"text/template"
 
 	"github.com/pkg/errors"
-
-	"github.com/bytemare/goproject/internal/config"
 )
 
 // fileTemplate are structures holding basic values for the file and corresponding template
@@ -50,10 +46,6 @@ func (f *file) getFile() *file {
 	return f
 }
 
-func (f *file) write() error {
-	return ioutil.WriteFile(path.Join(f.directory, f.filename), []byte(f.content), config.FileMode)
-}
-
 func newFile(identifier, filename, directory, rawTemplate string) *file {
 	return &file{
 		identifier: identifier,
@@ -63,45 +55,91 @@ func newFile(identifier, filename, directory, rawTemplate string) *file {
 	}
 }
 
-// getFileConstructor returns a registered projectFile constructor associated with the given file identifier
-func getFileConstructor(fileID string) (constructor func(*Project) (*file, error), err error) {
+// constructor renders the files of a template for the given project
+type constructor func(*Project) ([]*file, error)
+
+// single adapts the constructor of a template producing a single file
+func single(c func(*Project) (*file, error)) constructor {
+	return func(project *Project) ([]*file, error) {
+		f, err := c(project)
+		if err != nil {
+			return nil, err
+		}
+
+		return []*file{f}, nil
+	}
+}
+
+// builtinTemplates returns the constructors of the templates shipped with goproject, indexed by their identifier
+func builtinTemplates() map[string]constructor {
 	// Reference all available templates here
-	registeredTemplates := map[string]func(*Project) (*file, error){
-		docIdentifier:        docConstructor,
-		dockerfileIdentifier: dockerfileConstructor,
-		gitignoreIdentifier:  gitignoreConstructor,
-		golangciIdentifier:   golangciConstructor,
-		makefileIdentifier:   makefileConstructor,
-		precommitIdentifier:  precommitConstructor,
-		readmeIdentifier:     readmeConstructor,
-		sonarIdentifier:      sonarConstructor,
-		travisIdentifier:     travisConstructor,
-		versionIdentifier:    versionConstructor,
+	return map[string]constructor{
+		docIdentifier:        single(docConstructor),
+		dockerfileIdentifier: single(dockerfileConstructor),
+		gitignoreIdentifier:  single(gitignoreConstructor),
+		golangciIdentifier:   single(golangciConstructor),
+		makefileIdentifier:   single(makefileConstructor),
+		precommitIdentifier:  single(precommitConstructor),
+		readmeIdentifier:     single(readmeConstructor),
+		sonarIdentifier:      single(sonarConstructor),
+		travisIdentifier:     single(travisConstructor),
+		versionIdentifier:    single(versionConstructor),
 	}
+}
 
-	constructor, ok := registeredTemplates[fileID]
+// getFileConstructor returns a registered projectFile constructor associated with the given file identifier.
+// User templates take precedence over built-in templates registered under the same identifier.
+func (p *Project) getFileConstructor(fileID string) (c constructor, err error) {
+	if t, ok := p.Profile.Templates[fileID]; ok {
+		return userConstructor(t), nil
+	}
+
+	c, ok := builtinTemplates()[fileID]
 	if !ok {
 		err = fmt.Errorf("error : '%s' is not a registered Project file", fileID)
 	}
 
-	return constructor, err
+	return c, err
 }
 
-func newProjectFile(ft fileTemplate) (*file, error) {
+// execute parses the raw template along with the partials, and processes it with the given values.
+// Partials are parsed after the template, so that they replace the default content of its blocks of the same name.
+func execute(name, raw string, values interface{}, partials map[string]string) (string, error) {
 	// Parse the template
-	tmpl, err := template.New(ft.getIdentifier()).Parse(ft.getTemplate())
+	tmpl, err := template.New(name).Funcs(funcMap()).Parse(raw)
 	if err != nil {
-		return nil, errors.Wrapf(err, "could not parse template string from '%s'", ft.getIdentifier())
+		return "", errors.Wrapf(err, "could not parse template string from '%s'", name)
+	}
+
+	for partialName, partial := range partials {
+		// A partial of the same name would silently replace the template itself
+		if partialName == name {
+			return "", errors.Errorf("partial '%s' has the name of the template it is used in, rename it", partialName)
+		}
+
+		if _, err := tmpl.New(partialName).Parse(partial); err != nil {
+			return "", errors.Wrapf(err, "could not parse partial '%s' for '%s'", partialName, name)
+		}
 	}
 
 	// Process the template with the associated values
 	var buff bytes.Buffer
-	if err := tmpl.Execute(&buff, ft); err != nil {
-		return nil, errors.Wrapf(err, "could not execute template for '%s' with values of '%v'", ft.getIdentifier(), ft)
+	if err := tmpl.Execute(&buff, values); err != nil {
+		return "", errors.Wrapf(err, "could not execute template for '%s' with values of '%v'", name, values)
+	}
+
+	return buff.String(), nil
+}
+
+// newProjectFile renders the template with the project's context
+func newProjectFile(project *Project, ft fileTemplate) (*file, error) {
+	content, err := execute(ft.getIdentifier(), ft.getTemplate(), project.context(), project.partials())
+	if err != nil {
+		return nil, err
 	}
 
 	f := ft.getFile()
-	f.content = buff.String()
+	f.content = content
 
 	return f, nil
 }
@@ -0,0 +1,49 @@
+package templates
+
+import (
+	"testing"
+)
+
+func TestExecutePartials(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		partials map[string]string
+		want     string
+		err      bool
+	}{
+		{
+			name:     "call",
+			raw:      `a {{template "p" .}} c`,
+			partials: map[string]string{"p": "b"},
+			want:     "a b c",
+		},
+		{
+			name: "block default",
+			raw:  `a {{block "p" .}}default{{end}} c`,
+			want: "a default c",
+		},
+		{
+			name:     "block replaced",
+			raw:      `a {{block "p" .}}default{{end}} c`,
+			partials: map[string]string{"p": "b"},
+			want:     "a b c",
+		},
+		{
+			name:     "partial named as the template",
+			raw:      "content",
+			partials: map[string]string{"tmpl": "replaced"},
+			err:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := execute("tmpl", tt.raw, nil, tt.partials)
+
+			if tt.err != (err != nil) || got != tt.want {
+				t.Errorf("execute = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
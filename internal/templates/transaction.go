@@ -0,0 +1,281 @@
+// Package templates holds the template and project building functions
+package templates
+
+import (
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/bytemare/goproject/internal/config"
+	"github.com/bytemare/goproject/internal/vfs"
+
+	"github.com/pkg/errors"
+)
+
+// stashSuffix is appended to the files of the project replaced by a generation, until it succeeds
+const stashSuffix = ".goproject-old"
+
+// transaction stages a generation in a temporary directory, and then moves it into the project.
+// Nothing is written to the project before everything was staged, and if moving fails, the project is restored.
+type transaction struct {
+	// fs is the file system of the project, staging the directory the generation is staged in on it, and target the
+	// project directory
+	fs      vfs.FS
+	staging string
+	target  string
+
+	// out receives the progress of the generation
+	out io.Writer
+
+	// mu guards the journal, the staged files and the backups, as files are staged concurrently
+	mu sync.Mutex
+
+	// journal records what the generation creates in the project
+	journal *journal
+
+	// staged lists the files and directories to move into the project, in order, relative to the staging directory
+	staged []string
+
+	// backups holds, for the files to back up before replacing them, the name of the backup
+	backups map[string]string
+
+	// reverts undoes, in reverse order, the changes made to the project, and stashes lists the replaced files
+	reverts []func() error
+	stashes []string
+}
+
+// newTransaction creates the staging directory of a generation of the project in the absolute target directory
+func newTransaction(fs vfs.FS, target string, out io.Writer) (*transaction, error) {
+	staging, err := fs.MkdirTemp("goproject-")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create staging directory")
+	}
+
+	return &transaction{
+		fs:      fs,
+		staging: staging,
+		target:  target,
+		out:     out,
+		journal: &journal{},
+		backups: make(map[string]string),
+	}, nil
+}
+
+// close removes the staging directory
+func (t *transaction) close() {
+	_ = t.fs.RemoveAll(t.staging)
+}
+
+// mkdir creates the directory in the staging directory, and records those of its parents missing in the project
+func (t *transaction) mkdir(dir string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var missing []string
+
+	for d := path.Clean(dir); d != "." && d != "/"; d = path.Dir(d) {
+		if t.hasDir(d) {
+			break
+		}
+
+		e, err := vfs.Exists(t.fs, filepath.Join(t.target, d))
+		if err != nil {
+			return err
+		}
+
+		if e {
+			break
+		}
+
+		missing = append([]string{d}, missing...)
+	}
+
+	if err := t.fs.MkdirAll(filepath.Join(t.staging, dir), config.DirMode); err != nil {
+		return err
+	}
+
+	t.journal.Directories = append(t.journal.Directories, missing...)
+
+	return nil
+}
+
+// hasDir returns whether the directory was already recorded
+func (t *transaction) hasDir(dir string) bool {
+	for _, d := range t.journal.Directories {
+		if d == dir {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeFile stages the file, creating its directory if needed, as templates may target directories that are not part
+// of the layout. The file is recorded if it does not exist in the project.
+func (t *transaction) writeFile(name, content string) error {
+	if err := t.mkdir(path.Dir(name)); err != nil {
+		return err
+	}
+
+	if err := t.fs.WriteFile(filepath.Join(t.staging, name), []byte(content), config.FileMode); err != nil {
+		return err
+	}
+
+	return t.stage(name, content)
+}
+
+// stage adds a file or directory of the staging directory to the ones to move into the project. Files are recorded
+// with their content if they do not exist in the project.
+func (t *transaction) stage(name, content string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, s := range t.staged {
+		if s == name {
+			return nil
+		}
+	}
+
+	t.staged = append(t.staged, name)
+
+	e, err := vfs.Exists(t.fs, filepath.Join(t.target, name))
+	if err != nil {
+		return err
+	}
+
+	if !e && name != ".git" {
+		t.journal.record(name, content)
+	}
+
+	return nil
+}
+
+// backup tells to back up the file of the project before replacing it
+func (t *transaction) backup(name, backup string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.backups[name] = backup
+}
+
+// sort orders what was staged concurrently by path, so that the generation does not depend on scheduling.
+// Directories remain created before their subdirectories.
+func (t *transaction) sort() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	sort.Strings(t.staged)
+	sort.Strings(t.journal.Directories)
+	sort.Slice(t.journal.Files, func(i, j int) bool {
+		return t.journal.Files[i].Path < t.journal.Files[j].Path
+	})
+}
+
+// commit moves the staged generation into the project. If anything fails, the changes already made are reverted.
+func (t *transaction) commit() error {
+	if err := t.apply(); err != nil {
+		if rerr := t.rollback(); rerr != nil {
+			return errors.Wrapf(err, "could not roll back (%v)", rerr)
+		}
+
+		return errors.Wrap(err, "nothing was written")
+	}
+
+	// The replaced files are no longer needed
+	for _, s := range t.stashes {
+		_ = t.fs.Remove(s)
+	}
+
+	return nil
+}
+
+// apply creates the project directory and the missing directories, and moves the staged files into the project
+func (t *transaction) apply() error {
+	if t.journal.Root != "" {
+		if err := t.fs.MkdirAll(t.target, config.DirMode); err != nil {
+			return err
+		}
+
+		t.reverts = append(t.reverts, func() error {
+			return t.fs.Remove(t.target)
+		})
+	}
+
+	for _, d := range t.journal.Directories {
+		dir := filepath.Join(t.target, d)
+		if err := t.fs.Mkdir(dir, config.DirMode); err != nil {
+			return err
+		}
+
+		t.reverts = append(t.reverts, func() error {
+			return t.fs.Remove(dir)
+		})
+	}
+
+	for _, name := range t.staged {
+		if err := t.move(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// move moves a staged file or directory into the project. A file it replaces is moved aside, to its backup if
+// required, so that it can be restored.
+func (t *transaction) move(name string) error {
+	src, dst := filepath.Join(t.staging, name), filepath.Join(t.target, name)
+
+	info, err := t.fs.Lstat(dst)
+
+	switch {
+	case err == nil:
+		aside := dst + stashSuffix
+		if b, ok := t.backups[name]; ok {
+			aside = filepath.Join(t.target, b)
+		} else {
+			t.stashes = append(t.stashes, aside)
+		}
+
+		// The new file keeps the permissions of the one it replaces
+		if err := t.fs.Chmod(src, info.Mode().Perm()); err != nil {
+			return err
+		}
+
+		if err := t.fs.Rename(dst, aside); err != nil {
+			return err
+		}
+
+		t.reverts = append(t.reverts, func() error {
+			return t.fs.Rename(aside, dst)
+		})
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	if err := t.fs.Rename(src, dst); err != nil {
+		return err
+	}
+
+	t.reverts = append(t.reverts, func() error {
+		return t.fs.RemoveAll(dst)
+	})
+
+	return nil
+}
+
+// rollback reverts the changes made to the project, in reverse order
+func (t *transaction) rollback() error {
+	var res error
+
+	for i := len(t.reverts) - 1; i >= 0; i-- {
+		if err := t.reverts[i](); err != nil && res == nil {
+			res = err
+		}
+	}
+
+	return res
+}
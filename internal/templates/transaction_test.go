@@ -0,0 +1,147 @@
+package templates
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bytemare/goproject/internal/config"
+	"github.com/bytemare/goproject/internal/vfs"
+
+	"github.com/pkg/errors"
+)
+
+// newMemory returns a memory file system holding the files, /work, and the temporary directory as a disk does
+func newMemory(t *testing.T, files map[string]string) *vfs.Memory {
+	t.Helper()
+
+	fs := vfs.NewMemory()
+
+	for _, dir := range []string{"/work", os.TempDir()} {
+		if err := fs.MkdirAll(dir, config.DirMode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, content := range files {
+		if err := fs.MkdirAll(filepath.Dir(name), config.DirMode); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := fs.WriteFile(name, []byte(content), config.FileMode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return fs
+}
+
+// tree returns the files of the file system with their content, and its directories with an empty one
+func tree(t *testing.T, fs vfs.FS) map[string]string {
+	t.Helper()
+
+	res := make(map[string]string)
+
+	err := vfs.Walk(fs, "/", func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			res[name] = ""
+			return nil
+		}
+
+		content, err := fs.ReadFile(name)
+		res[name] = string(content)
+
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res
+}
+
+// failingFS fails to rename the named file
+type failingFS struct {
+	*vfs.Memory
+	name string
+}
+
+func (fs *failingFS) Rename(oldname, newname string) error {
+	if newname == fs.name {
+		return errors.New("rename failed")
+	}
+
+	return fs.Memory.Rename(oldname, newname)
+}
+
+func TestCommitRollback(t *testing.T) {
+	fs := &failingFS{Memory: newMemory(t, map[string]string{"/work/app/a": "a"}), name: "/work/app/dir/z"}
+	before := tree(t, fs)
+
+	tr, err := newTransaction(fs, "/work/app", ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tr.close()
+
+	// The file replaced and the directory created before the failure are restored and removed
+	for name, content := range map[string]string{"a": "replaced", "dir/b": "b", "dir/z": "z"} {
+		if err := tr.writeFile(name, content); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tr.sort()
+
+	if err := tr.commit(); err == nil {
+		t.Fatal("commit succeeded")
+	}
+
+	tr.close()
+
+	if got := tree(t, fs); !reflect.DeepEqual(got, before) {
+		t.Errorf("got %v after rollback, want %v", got, before)
+	}
+}
+
+func TestBuildFailure(t *testing.T) {
+	prof, err := config.ParseProfile("failure.toml", []byte(`
+[layout]
+directories = ["cmd"]
+files = ["doc", "broken"]
+
+[templates.broken]
+filename = "broken.txt"
+template = "{{required \"broken needs a value\" .Vars.missing}}"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := NewProject(prof, "app", "/work", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := newMemory(t, map[string]string{"/work/app/a": "a"})
+	project.FS = fs
+	project.Out = ioutil.Discard
+
+	before := tree(t, fs)
+
+	_, err = project.Build()
+
+	if e, ok := err.(*BuildError); !ok || e.Written || len(e.Failures) != 1 {
+		t.Fatalf("got error %v, want a build error with one failure, and nothing written", err)
+	}
+
+	if got := tree(t, fs); !reflect.DeepEqual(got, before) {
+		t.Errorf("got %v after a failed build, want %v", got, before)
+	}
+}
@@ -3,41 +3,10 @@ package templates
 
 const travisIdentifier = "travis"
 
-type travis struct {
-	*file
-	RepoURL  string
-	SonarOrg string
-}
-
 // travisConstructor returns the file content populated with the relevant values
 func travisConstructor(project *Project) (*file, error) {
-	conf := project.Profile.Conf
-	sonarOrg := conf.GetString("sonar.org")
-	repoURL := conf.GetString("git.URL")
-
-	return newProjectFile(newTravis(repoURL, sonarOrg))
-}
-
-func newTravis(repoURL, sonarOrg string) *travis {
 	f, d, t := travisValues()
-
-	return &travis{
-		file:     newFile(travisIdentifier, f, d, t),
-		RepoURL:  repoURL,
-		SonarOrg: sonarOrg,
-	}
-}
-
-func (t *travis) getIdentifier() string {
-	return t.identifier
-}
-
-func (t *travis) getFilename() string {
-	return t.filename
-}
-
-func (t *travis) getTemplate() string {
-	return t.template
+	return newProjectFile(project, newFile(travisIdentifier, f, d, t))
 }
 
 func travisValues() (f, d, t string) {
@@ -77,7 +46,7 @@ addons:
 jobs:
   include:
     - stage: "Static Analysis, Unit Tests and Coverage"
-      go: 1.14.x
+      go: {{template "go-version"}}.x
       name: "GolangCI Linting, and Snyk Analysis"
       os: linux
       install:
@@ -89,12 +58,12 @@ jobs:
         - snyk test
       after_success:
         - snyk monitor
-    - go: 1.14.x
+    - go: {{template "go-version"}}.x
       name: "Unit Tests and Coverage"
-      {{if .SonarOrg -}}
+      {{if .Sonar.Org -}}
       addons:
         sonarcloud:
-          organization: "{{.SonarOrg}}"
+          organization: "{{.Sonar.Org}}"
           token:
             secure: ${SONAR_TOKEN}
 	  {{end}}
@@ -122,7 +91,7 @@ go:
   - 1.11.x
   - 1.12.x
   - 1.13.x
-  - 1.14.x
+  - {{template "go-version"}}.x
 os:
   - linux
   - osx
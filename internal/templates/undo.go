@@ -0,0 +1,171 @@
+// Package templates holds the template and project building functions
+package templates
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/bytemare/goproject/internal/vfs"
+
+	"github.com/pkg/errors"
+)
+
+// undo removes what a generation created, and collects what could not be removed
+type undo struct {
+	fs     vfs.FS
+	out    io.Writer
+	failed failures
+}
+
+// Undo removes what the last generation of the project in the directory created, as recorded in its journal, from the
+// file system, and writes its progress to out.
+// Files modified since, directories that are not empty, and git repositories with commits other than the generation's
+// are kept. If some could not be removed, the others are removed nonetheless and the returned UndoError lists them.
+func Undo(fs vfs.FS, dir string, out io.Writer) error {
+	t, err := readJournal(fs, dir)
+	if err != nil {
+		return err
+	}
+
+	u := &undo{fs: fs, out: out}
+
+	if len(t.Files) != 0 {
+		fmt.Fprintln(out, "Removing files.")
+	}
+
+	for _, f := range t.Files {
+		fmt.Fprintf(out, "\t> Removing file %s ... ", f.Path)
+		u.file(filepath.Join(dir, f.Path), f.Hash)
+	}
+
+	if t.GitInit {
+		fmt.Fprintf(out, "Removing git repository ... ")
+		u.git(dir, t.GitCommit)
+	}
+
+	if err := fs.Remove(filepath.Join(dir, journalName)); err != nil {
+		return errors.Wrap(err, "could not remove journal")
+	}
+
+	if len(t.Directories) != 0 {
+		fmt.Fprintln(out, "Removing directories.")
+	}
+
+	// Children were created after their parents
+	for i := len(t.Directories) - 1; i >= 0; i-- {
+		fmt.Fprintf(out, "\t> Removing directory %s ... ", t.Directories[i])
+		u.dir(filepath.Join(dir, t.Directories[i]))
+	}
+
+	if t.Root != "" {
+		fmt.Fprintf(out, "Removing project directory %s ... ", t.Root)
+		u.dir(t.Root)
+	}
+
+	if len(u.failed) == 0 {
+		return nil
+	}
+
+	return &UndoError{Failures: u.failed}
+}
+
+// fail reports that the named step could not be undone
+func (u *undo) fail(step, name string, err error) {
+	fmt.Fprintf(u.out, buildErrFormat, err)
+	u.failed.add(step, name, err)
+}
+
+// file removes the file if its content has the hash it was created with
+func (u *undo) file(name, h string) {
+	content, err := u.fs.ReadFile(name)
+
+	switch {
+	case os.IsNotExist(err):
+		fmt.Fprintln(u.out, "already removed.")
+	case err != nil:
+		u.fail(StepFile, name, err)
+	case hash(string(content)) != h:
+		fmt.Fprintln(u.out, "modified since it was generated. Keeping.")
+	default:
+		if err := u.fs.Remove(name); err != nil {
+			u.fail(StepFile, name, err)
+		} else {
+			fmt.Fprintln(u.out, "success.")
+		}
+	}
+}
+
+// dir removes the directory if it is empty
+func (u *undo) dir(dir string) {
+	entries, err := u.fs.ReadDir(dir)
+
+	switch {
+	case os.IsNotExist(err):
+		fmt.Fprintln(u.out, "already removed.")
+	case err != nil:
+		u.fail(StepDirectory, dir, err)
+	case len(entries) != 0:
+		fmt.Fprintln(u.out, "not empty. Keeping.")
+	default:
+		if err := u.fs.Remove(dir); err != nil {
+			u.fail(StepDirectory, dir, err)
+		} else {
+			fmt.Fprintln(u.out, "success.")
+		}
+	}
+}
+
+// git removes the git repository of the project if nothing was committed to it but the initial commit of the
+// generation
+func (u *undo) git(dir, initial string) {
+	gitDir := filepath.Join(dir, ".git")
+
+	commits, err := u.commits(gitDir)
+	if err != nil {
+		u.fail(StepGit, gitDir, err)
+		return
+	}
+
+	if len(commits) > 1 || len(commits) == 1 && commits[0] != initial {
+		fmt.Fprintln(u.out, "it has commits. Keeping.")
+		return
+	}
+
+	if err := u.fs.RemoveAll(gitDir); err != nil {
+		u.fail(StepGit, gitDir, err)
+	} else {
+		fmt.Fprintln(u.out, "success.")
+	}
+}
+
+// commits lists the commits of the git repository
+func (u *undo) commits(gitDir string) ([]string, error) {
+	// git runs on the disk : on another file system, it runs on a copy of the repository
+	if _, ok := u.fs.(vfs.OS); !ok {
+		tmp, err := ioutil.TempDir("", "goproject-")
+		if err != nil {
+			return nil, errors.Wrap(err, "could not copy git repository")
+		}
+		defer os.RemoveAll(tmp)
+
+		if err := vfs.Copy(vfs.OS{}, filepath.Join(tmp, ".git"), u.fs, gitDir); err != nil {
+			return nil, errors.Wrap(err, "could not copy git repository")
+		}
+
+		gitDir = filepath.Join(tmp, ".git")
+	}
+
+	cmd := exec.Command("git", "--git-dir", gitDir, "rev-list", "--all")
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list commits")
+	}
+
+	return strings.Fields(string(out)), nil
+}
@@ -0,0 +1,65 @@
+package templates
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"reflect"
+	"testing"
+
+	"github.com/bytemare/goproject/internal/config"
+)
+
+const undoProfile = `
+[author]
+name = "Bytemare"
+contact = "dev@bytema.re"
+
+[layout]
+directories = ["cmd", "internal/config"]
+files = ["doc", "extra"]
+
+[git]
+user = "bytemare"
+mail = "dev@bytema.re"
+commit = true
+
+[templates.extra]
+filename = "docs/{{.Project.Name}}.md"
+template = "# {{.Project.Name}}\n"
+`
+
+func TestUndo(t *testing.T) {
+	for _, bin := range []string{"go", "git"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			t.Skipf("%s is not installed", bin)
+		}
+	}
+
+	prof, err := config.ParseProfile("undo.toml", []byte(undoProfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := NewProject(prof, "app", "/work", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := newMemory(t, nil)
+	project.FS = fs
+	project.Out = ioutil.Discard
+
+	before := tree(t, fs)
+
+	if _, err := project.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Undo(fs, "/work/app", ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := tree(t, fs); !reflect.DeepEqual(got, before) {
+		t.Errorf("got %v after undo, want %v", got, before)
+	}
+}
@@ -0,0 +1,56 @@
+// Package templates holds the template and project building functions
+package templates
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/bytemare/goproject/internal/config"
+)
+
+// userConstructor returns a constructor rendering the given user template. Next to its optional single file,
+// a user template can hold a whole directory tree, in which case both the paths and the contents are rendered.
+func userConstructor(t *config.Template) constructor {
+	return func(project *Project) ([]*file, error) {
+		files := make([]*file, 0, len(t.Files)+1)
+
+		if t.Filename != "" {
+			f, err := newProjectFile(project, newFile(t.Identifier, t.Filename, t.Directory, t.Template))
+			if err != nil {
+				return nil, err
+			}
+
+			files = append(files, f)
+		}
+
+		for _, tf := range t.Files {
+			f, err := newTreeFile(t.Identifier, tf, project)
+			if err != nil {
+				return nil, err
+			}
+
+			files = append(files, f)
+		}
+
+		return files, nil
+	}
+}
+
+// newTreeFile renders the path and the content of a file of a template tree
+func newTreeFile(identifier string, tf *config.TemplateFile, project *Project) (*file, error) {
+	name := fmt.Sprintf("%s:%s", identifier, tf.Path)
+
+	// The path is itself a template, rendered with the same values as the content
+	filePath, err := execute(name, tf.Path, project.context(), project.partials())
+	if err != nil {
+		return nil, err
+	}
+
+	filePath = path.Clean(filePath)
+	if path.IsAbs(filePath) || filePath == ".." || strings.HasPrefix(filePath, "../") {
+		return nil, fmt.Errorf("error : file '%s' of template '%s' resolves outside of the project", filePath, identifier)
+	}
+
+	return newProjectFile(project, newFile(identifier, path.Base(filePath), path.Dir(filePath), tf.Template))
+}
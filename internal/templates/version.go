@@ -4,9 +4,9 @@ package templates
 const versionIdentifier = "version"
 
 // docConstructor returns the file content populated with the relevant values
-func versionConstructor(project *Project) (*file, error) { //nolint:unparam // project is not needed when no variables
+func versionConstructor(project *Project) (*file, error) {
 	f, d, t := versionValues()
-	return newProjectFile(newFile(versionIdentifier, f, d, t))
+	return newProjectFile(project, newFile(versionIdentifier, f, d, t))
 }
 
 func versionValues() (f, d, t string) {
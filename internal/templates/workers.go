@@ -0,0 +1,47 @@
+// Package templates holds the template and project building functions
+package templates
+
+import (
+	"runtime"
+	"sync"
+)
+
+// workers returns the number of templates to render or write concurrently, the number of CPUs if not set
+func (p *Project) workers() int {
+	if p.Jobs > 0 {
+		return p.Jobs
+	}
+
+	return runtime.NumCPU()
+}
+
+// parallel calls job for each index up to n, with at most workers calls running at the same time.
+// It returns once all calls returned. Jobs are expected to store their results by index, to keep them in order.
+func parallel(n, workers int, job func(i int)) {
+	if workers > n {
+		workers = n
+	}
+
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	wg.Add(workers)
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				job(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+}
@@ -0,0 +1,170 @@
+// Package vfs abstracts the file systems projects are generated into : the disk, memory, or an overlay of memory
+// over another file system.
+package vfs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Archive formats
+const (
+	ArchiveTarGz = "tar.gz"
+	ArchiveZip   = "zip"
+)
+
+// ArchiveFormat returns the format of the archive of the given file name : zip for .zip, and tar.gz for .tar.gz, .tgz,
+// and - for the standard output
+func ArchiveFormat(name string) (string, error) {
+	switch {
+	case name == "-", strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return ArchiveTarGz, nil
+	case strings.HasSuffix(name, ".zip"):
+		return ArchiveZip, nil
+	default:
+		return "", fmt.Errorf("unsupported archive '%s', expected a .tar.gz, .tgz or .zip file, or -", name)
+	}
+}
+
+// archiver adds files and directories to an archive, given their name in the archive
+type archiver interface {
+	add(name string, info os.FileInfo, data []byte) error
+	Close() error
+}
+
+// WriteArchive writes the directory and its content into an archive of the given format, under the prefix directory,
+// keeping their permissions. The excluded names, relative to the directory, are left out.
+func WriteArchive(w io.Writer, format string, fs FS, dir, prefix string, exclude ...string) error {
+	var a archiver
+
+	switch format {
+	case ArchiveTarGz:
+		a = newTarArchiver(w)
+	case ArchiveZip:
+		a = &zipArchiver{zip.NewWriter(w)}
+	default:
+		return fmt.Errorf("unsupported archive format '%s'", format)
+	}
+
+	excluded := make(map[string]bool, len(exclude))
+	for _, e := range exclude {
+		excluded[clean(e)] = true
+	}
+
+	err := Walk(fs, dir, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+
+		rel = clean(rel)
+
+		if excluded[rel] {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		var data []byte
+
+		if !info.IsDir() {
+			if data, err = fs.ReadFile(name); err != nil {
+				return err
+			}
+		}
+
+		return a.add(path.Join(prefix, rel), info, data)
+	})
+	if err != nil {
+		_ = a.Close()
+		return err
+	}
+
+	return a.Close()
+}
+
+// tarArchiver writes a gzip compressed tar archive
+type tarArchiver struct {
+	gz  *gzip.Writer
+	tar *tar.Writer
+}
+
+func newTarArchiver(w io.Writer) *tarArchiver {
+	gz := gzip.NewWriter(w)
+	return &tarArchiver{gz: gz, tar: tar.NewWriter(gz)}
+}
+
+func (a *tarArchiver) add(name string, info os.FileInfo, data []byte) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    int64(info.Mode().Perm()),
+		ModTime: info.ModTime(),
+	}
+
+	if info.IsDir() {
+		header.Typeflag = tar.TypeDir
+		header.Name += "/"
+	} else {
+		header.Typeflag = tar.TypeReg
+		header.Size = int64(len(data))
+	}
+
+	if err := a.tar.WriteHeader(header); err != nil {
+		return err
+	}
+
+	_, err := a.tar.Write(data)
+
+	return err
+}
+
+func (a *tarArchiver) Close() error {
+	if err := a.tar.Close(); err != nil {
+		_ = a.gz.Close()
+		return err
+	}
+
+	return a.gz.Close()
+}
+
+// zipArchiver writes a zip archive
+type zipArchiver struct {
+	*zip.Writer
+}
+
+func (a *zipArchiver) add(name string, info os.FileInfo, data []byte) error {
+	header := &zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: info.ModTime(),
+	}
+
+	header.SetMode(info.Mode())
+
+	if info.IsDir() {
+		header.Name += "/"
+		header.Method = zip.Store
+	}
+
+	w, err := a.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+
+	return err
+}
@@ -0,0 +1,359 @@
+// Package vfs abstracts the file systems projects are generated into : the disk, memory, or an overlay of memory
+// over another file system.
+package vfs
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Memory is a file system held in memory. It is safe for concurrent use.
+type Memory struct {
+	mu    sync.RWMutex
+	files map[string]*memFile
+	temp  int
+}
+
+// memFile is a file or a directory of a memory file system
+type memFile struct {
+	data    []byte
+	mode    os.FileMode
+	modTime time.Time
+}
+
+// NewMemory returns an empty memory file system
+func NewMemory() *Memory {
+	return &Memory{files: make(map[string]*memFile)}
+}
+
+// Stat returns the FileInfo of the named file
+func (m *Memory) Stat(name string) (os.FileInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.stat("stat", clean(name))
+}
+
+// Lstat returns the FileInfo of the named file, as there are no symbolic links in memory
+func (m *Memory) Lstat(name string) (os.FileInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.stat("lstat", clean(name))
+}
+
+// ReadFile returns the content of the named file
+func (m *Memory) ReadFile(name string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	name = clean(name)
+
+	f, err := m.file("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.mode.IsDir() {
+		return nil, &os.PathError{Op: "read", Path: name, Err: syscall.EISDIR}
+	}
+
+	return append([]byte(nil), f.data...), nil
+}
+
+// WriteFile writes data to the named file, creating it with the given permissions if needed
+func (m *Memory) WriteFile(name string, data []byte, perm os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = clean(name)
+
+	if f, ok := m.files[name]; ok {
+		if f.mode.IsDir() || isRoot(name) {
+			return &os.PathError{Op: "open", Path: name, Err: syscall.EISDIR}
+		}
+
+		f.data = append([]byte(nil), data...)
+		f.modTime = time.Now()
+
+		return nil
+	}
+
+	if err := m.parent("open", name); err != nil {
+		return err
+	}
+
+	m.files[name] = &memFile{data: append([]byte(nil), data...), mode: perm.Perm(), modTime: time.Now()}
+
+	return nil
+}
+
+// ReadDir returns the entries of the named directory, sorted by name
+func (m *Memory) ReadDir(name string) ([]os.FileInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	name = clean(name)
+
+	f, err := m.file("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !f.mode.IsDir() {
+		return nil, &os.PathError{Op: "readdirent", Path: name, Err: syscall.ENOTDIR}
+	}
+
+	var entries []os.FileInfo
+
+	for n, f := range m.files {
+		if path.Dir(n) == name && n != name {
+			entries = append(entries, f.info(n))
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	return entries, nil
+}
+
+// Mkdir creates the named directory
+func (m *Memory) Mkdir(name string, perm os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.mkdir(clean(name), perm)
+}
+
+// MkdirAll creates the named directory, along with its missing parents
+func (m *Memory) MkdirAll(name string, perm os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.mkdirAll(clean(name), perm)
+}
+
+// MkdirTemp creates a new directory in the default directory for temporary files
+func (m *Memory) MkdirTemp(pattern string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for {
+		m.temp++
+
+		name := path.Join(clean(os.TempDir()), fmt.Sprintf("%s%d", pattern, m.temp))
+		if _, ok := m.files[name]; ok {
+			continue
+		}
+
+		return name, m.mkdirAll(name, 0700)
+	}
+}
+
+// Chmod changes the mode of the named file
+func (m *Memory) Chmod(name string, mode os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = clean(name)
+
+	f, err := m.file("chmod", name)
+	if err != nil {
+		return err
+	}
+
+	if !isRoot(name) {
+		f.mode = f.mode&os.ModeType | mode.Perm()
+	}
+
+	return nil
+}
+
+// Rename moves a file or a directory and its content
+func (m *Memory) Rename(oldname, newname string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	oldname, newname = clean(oldname), clean(newname)
+
+	f, ok := m.files[oldname]
+	if !ok || isRoot(oldname) {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: os.ErrNotExist}
+	}
+
+	if within(newname, oldname) {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: syscall.EINVAL}
+	}
+
+	if existing, ok := m.files[newname]; ok && (existing.mode.IsDir() || f.mode.IsDir()) || isRoot(newname) {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: os.ErrExist}
+	}
+
+	if err := m.parent("rename", newname); err != nil {
+		return err
+	}
+
+	for n, f := range m.files {
+		if n == oldname || within(n, oldname) {
+			delete(m.files, n)
+			m.files[newname+strings.TrimPrefix(n, oldname)] = f
+		}
+	}
+
+	return nil
+}
+
+// Remove removes the named file or empty directory
+func (m *Memory) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = clean(name)
+
+	if _, err := m.file("remove", name); err != nil {
+		return err
+	}
+
+	for n := range m.files {
+		if within(n, name) {
+			return &os.PathError{Op: "remove", Path: name, Err: syscall.ENOTEMPTY}
+		}
+	}
+
+	if isRoot(name) {
+		return &os.PathError{Op: "remove", Path: name, Err: syscall.EBUSY}
+	}
+
+	delete(m.files, name)
+
+	return nil
+}
+
+// RemoveAll removes the named file or directory and its content
+func (m *Memory) RemoveAll(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = clean(name)
+
+	for n := range m.files {
+		if n == name || within(n, name) {
+			delete(m.files, n)
+		}
+	}
+
+	return nil
+}
+
+// file returns the named file, or a path error for the operation if it does not exist
+func (m *Memory) file(op, name string) (*memFile, error) {
+	if isRoot(name) {
+		return &memFile{mode: os.ModeDir | 0755}, nil
+	}
+
+	f, ok := m.files[name]
+	if !ok {
+		return nil, &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+	}
+
+	return f, nil
+}
+
+func (m *Memory) stat(op, name string) (os.FileInfo, error) {
+	f, err := m.file(op, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.info(name), nil
+}
+
+// parent returns an error for the operation on the named file if its parent is not an existing directory
+func (m *Memory) parent(op, name string) error {
+	f, err := m.file(op, path.Dir(name))
+	if err != nil {
+		return &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+	}
+
+	if !f.mode.IsDir() {
+		return &os.PathError{Op: op, Path: name, Err: syscall.ENOTDIR}
+	}
+
+	return nil
+}
+
+func (m *Memory) mkdir(name string, perm os.FileMode) error {
+	if _, ok := m.files[name]; ok || isRoot(name) {
+		return &os.PathError{Op: "mkdir", Path: name, Err: os.ErrExist}
+	}
+
+	if err := m.parent("mkdir", name); err != nil {
+		return err
+	}
+
+	m.files[name] = &memFile{mode: os.ModeDir | perm.Perm(), modTime: time.Now()}
+
+	return nil
+}
+
+func (m *Memory) mkdirAll(name string, perm os.FileMode) error {
+	f, err := m.file("mkdir", name)
+	if err == nil {
+		if f.mode.IsDir() {
+			return nil
+		}
+
+		return &os.PathError{Op: "mkdir", Path: name, Err: syscall.ENOTDIR}
+	}
+
+	if err := m.mkdirAll(path.Dir(name), perm); err != nil {
+		return err
+	}
+
+	return m.mkdir(name, perm)
+}
+
+// info returns the FileInfo of the file of the given name
+func (f *memFile) info(name string) os.FileInfo {
+	return &fileInfo{
+		name:    path.Base(name),
+		size:    int64(len(f.data)),
+		mode:    f.mode,
+		modTime: f.modTime,
+	}
+}
+
+// within returns whether the cleaned name is in the cleaned directory, at any depth
+func within(name, dir string) bool {
+	switch dir {
+	case "/":
+		return name != "/" && strings.HasPrefix(name, "/")
+	case ".":
+		return name != "." && !path.IsAbs(name)
+	default:
+		return strings.HasPrefix(name, dir+"/")
+	}
+}
+
+// fileInfo describes a file of a memory file system
+type fileInfo struct {
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+}
+
+func (i *fileInfo) Name() string       { return i.name }
+func (i *fileInfo) Size() int64        { return i.size }
+func (i *fileInfo) Mode() os.FileMode  { return i.mode }
+func (i *fileInfo) ModTime() time.Time { return i.modTime }
+func (i *fileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *fileInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,83 @@
+// Package vfs abstracts the file systems projects are generated into : the disk, memory, or an overlay of memory
+// over another file system.
+package vfs
+
+import (
+	"io/ioutil"
+	"os"
+	"syscall"
+)
+
+// OS is the file system of the disk
+type OS struct{}
+
+// Stat returns the FileInfo of the named file, following symbolic links
+func (OS) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+// Lstat returns the FileInfo of the named file, without following symbolic links
+func (OS) Lstat(name string) (os.FileInfo, error) {
+	return os.Lstat(name)
+}
+
+// ReadFile returns the content of the named file
+func (OS) ReadFile(name string) ([]byte, error) {
+	return ioutil.ReadFile(name)
+}
+
+// WriteFile writes data to the named file, creating it with the given permissions if needed
+func (OS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return ioutil.WriteFile(name, data, perm)
+}
+
+// ReadDir returns the entries of the named directory, sorted by name
+func (OS) ReadDir(name string) ([]os.FileInfo, error) {
+	return ioutil.ReadDir(name)
+}
+
+// Mkdir creates the named directory
+func (OS) Mkdir(name string, perm os.FileMode) error {
+	return os.Mkdir(name, perm)
+}
+
+// MkdirAll creates the named directory, along with its missing parents
+func (OS) MkdirAll(name string, perm os.FileMode) error {
+	return os.MkdirAll(name, perm)
+}
+
+// MkdirTemp creates a new directory in the default directory for temporary files
+func (OS) MkdirTemp(pattern string) (string, error) {
+	return ioutil.TempDir("", pattern)
+}
+
+// Chmod changes the mode of the named file
+func (OS) Chmod(name string, mode os.FileMode) error {
+	return os.Chmod(name, mode)
+}
+
+// Rename moves a file or a directory. If they are on different devices, e.g. from the directory for temporary files,
+// it is copied and then removed.
+func (fs OS) Rename(oldname, newname string) error {
+	err := os.Rename(oldname, newname)
+	if le, ok := err.(*os.LinkError); !ok || le.Err != syscall.EXDEV {
+		return err
+	}
+
+	if err := Copy(fs, newname, fs, oldname); err != nil {
+		_ = os.RemoveAll(newname)
+		return err
+	}
+
+	return os.RemoveAll(oldname)
+}
+
+// Remove removes the named file or empty directory
+func (OS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+// RemoveAll removes the named file or directory and its content
+func (OS) RemoveAll(name string) error {
+	return os.RemoveAll(name)
+}
@@ -0,0 +1,414 @@
+// Package vfs abstracts the file systems projects are generated into : the disk, memory, or an overlay of memory
+// over another file system.
+package vfs
+
+import (
+	"os"
+	"path"
+	"sort"
+	"sync"
+	"syscall"
+)
+
+// Overlay is a file system that reads through to a base file system, and holds all changes in memory :
+// the base is never written to. It is safe for concurrent use.
+type Overlay struct {
+	mu    sync.Mutex
+	base  FS
+	upper *Memory
+
+	// removed holds the names removed from the overlay, which hide the files of the base below them
+	removed map[string]bool
+}
+
+// NewOverlay returns an overlay over the base file system
+func NewOverlay(base FS) *Overlay {
+	return &Overlay{
+		base:    base,
+		upper:   NewMemory(),
+		removed: make(map[string]bool),
+	}
+}
+
+// Stat returns the FileInfo of the named file
+func (o *Overlay) Stat(name string) (os.FileInfo, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	info, _, err := o.stat(clean(name))
+
+	return info, err
+}
+
+// Lstat returns the FileInfo of the named file, without following symbolic links of the base
+func (o *Overlay) Lstat(name string) (os.FileInfo, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	name = clean(name)
+
+	if info, err := o.upper.Lstat(name); err == nil || o.hidden(name) {
+		return info, err
+	}
+
+	return o.base.Lstat(name)
+}
+
+// ReadFile returns the content of the named file
+func (o *Overlay) ReadFile(name string) ([]byte, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.readFile(clean(name))
+}
+
+// WriteFile writes data to the named file, creating it with the given permissions if needed
+func (o *Overlay) WriteFile(name string, data []byte, perm os.FileMode) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	name = clean(name)
+
+	// As on disk, an existing file keeps its permissions
+	if info, _, err := o.stat(name); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	if err := o.copyUpDir(path.Dir(name)); err != nil {
+		return err
+	}
+
+	return o.upper.WriteFile(name, data, perm)
+}
+
+// ReadDir returns the entries of the named directory, sorted by name
+func (o *Overlay) ReadDir(name string) ([]os.FileInfo, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.readDir(clean(name))
+}
+
+// Mkdir creates the named directory
+func (o *Overlay) Mkdir(name string, perm os.FileMode) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	name = clean(name)
+
+	if _, _, err := o.stat(name); err == nil {
+		return &os.PathError{Op: "mkdir", Path: name, Err: os.ErrExist}
+	}
+
+	if err := o.copyUpDir(path.Dir(name)); err != nil {
+		return err
+	}
+
+	return o.upper.Mkdir(name, perm)
+}
+
+// MkdirAll creates the named directory, along with its missing parents
+func (o *Overlay) MkdirAll(name string, perm os.FileMode) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.mkdirAll(clean(name), perm)
+}
+
+// MkdirTemp creates a new directory in memory, in the default directory for temporary files
+func (o *Overlay) MkdirTemp(pattern string) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.upper.MkdirTemp(pattern)
+}
+
+// Chmod changes the mode of the named file
+func (o *Overlay) Chmod(name string, mode os.FileMode) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	name = clean(name)
+
+	if err := o.copyUp(name); err != nil {
+		return err
+	}
+
+	return o.upper.Chmod(name, mode)
+}
+
+// Rename moves a file or a directory and its content
+func (o *Overlay) Rename(oldname, newname string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	oldname, newname = clean(oldname), clean(newname)
+
+	info, _, err := o.stat(oldname)
+	if err != nil {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: os.ErrNotExist}
+	}
+
+	if within(newname, oldname) || newname == oldname {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: syscall.EINVAL}
+	}
+
+	if existing, _, err := o.stat(newname); err == nil && (existing.IsDir() || info.IsDir()) {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: os.ErrExist}
+	}
+
+	if err := o.copyUpDir(path.Dir(newname)); err != nil {
+		return err
+	}
+
+	// What is renamed is copied in memory under its new name, and hidden under the old one
+	if err := o.copyTo(newname, oldname); err != nil {
+		return err
+	}
+
+	return o.removeAll(oldname)
+}
+
+// Remove removes the named file or empty directory
+func (o *Overlay) Remove(name string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	name = clean(name)
+
+	info, _, err := o.stat(name)
+	if err != nil {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+
+	if info.IsDir() {
+		entries, err := o.readDir(name)
+		if err != nil {
+			return err
+		}
+
+		if len(entries) != 0 {
+			return &os.PathError{Op: "remove", Path: name, Err: syscall.ENOTEMPTY}
+		}
+	}
+
+	return o.removeAll(name)
+}
+
+// RemoveAll removes the named file or directory and its content
+func (o *Overlay) RemoveAll(name string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.removeAll(clean(name))
+}
+
+// hidden returns whether the name, or one of its parents, was removed from the overlay
+func (o *Overlay) hidden(name string) bool {
+	for {
+		if o.removed[name] {
+			return true
+		}
+
+		parent := path.Dir(name)
+		if parent == name {
+			return false
+		}
+
+		name = parent
+	}
+}
+
+// stat returns the FileInfo of the named file, and whether it is held in memory
+func (o *Overlay) stat(name string) (os.FileInfo, bool, error) {
+	if info, err := o.upper.Stat(name); err == nil {
+		return info, true, nil
+	}
+
+	if o.hidden(name) {
+		return nil, false, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+	}
+
+	info, err := o.base.Stat(name)
+
+	return info, false, err
+}
+
+func (o *Overlay) readFile(name string) ([]byte, error) {
+	_, upper, err := o.stat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if upper {
+		return o.upper.ReadFile(name)
+	}
+
+	return o.base.ReadFile(name)
+}
+
+func (o *Overlay) readDir(name string) ([]os.FileInfo, error) {
+	info, upper, err := o.stat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return nil, &os.PathError{Op: "readdirent", Path: name, Err: syscall.ENOTDIR}
+	}
+
+	entries := make(map[string]os.FileInfo)
+
+	if !o.hidden(name) {
+		base, err := o.base.ReadDir(name)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		for _, e := range base {
+			if !o.removed[path.Join(name, e.Name())] {
+				entries[e.Name()] = e
+			}
+		}
+	}
+
+	if upper {
+		mem, err := o.upper.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range mem {
+			entries[e.Name()] = e
+		}
+	}
+
+	res := make([]os.FileInfo, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, e)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name() < res[j].Name()
+	})
+
+	return res, nil
+}
+
+func (o *Overlay) mkdirAll(name string, perm os.FileMode) error {
+	info, _, err := o.stat(name)
+	if err == nil {
+		if info.IsDir() {
+			return nil
+		}
+
+		return &os.PathError{Op: "mkdir", Path: name, Err: syscall.ENOTDIR}
+	}
+
+	if parent := path.Dir(name); parent != name {
+		if err := o.mkdirAll(parent, perm); err != nil {
+			return err
+		}
+
+		if err := o.copyUpDir(parent); err != nil {
+			return err
+		}
+	}
+
+	return o.upper.Mkdir(name, perm)
+}
+
+// copyUpDir makes sure the directory, and its parents, are held in memory, with the permissions of the base
+func (o *Overlay) copyUpDir(dir string) error {
+	if isRoot(dir) {
+		return nil
+	}
+
+	info, upper, err := o.stat(dir)
+	if err != nil {
+		return err
+	}
+
+	if upper {
+		return nil
+	}
+
+	if !info.IsDir() {
+		return &os.PathError{Op: "mkdir", Path: dir, Err: syscall.ENOTDIR}
+	}
+
+	if err := o.copyUpDir(path.Dir(dir)); err != nil {
+		return err
+	}
+
+	return o.upper.Mkdir(dir, info.Mode().Perm())
+}
+
+// copyUp makes sure the named file or directory is held in memory
+func (o *Overlay) copyUp(name string) error {
+	info, upper, err := o.stat(name)
+	if err != nil || upper {
+		return err
+	}
+
+	if info.IsDir() {
+		return o.copyUpDir(name)
+	}
+
+	if err := o.copyUpDir(path.Dir(name)); err != nil {
+		return err
+	}
+
+	data, err := o.base.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	return o.upper.WriteFile(name, data, info.Mode().Perm())
+}
+
+// copyTo copies the file or directory of the overlay into memory, under the new name
+func (o *Overlay) copyTo(newname, name string) error {
+	info, _, err := o.stat(name)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		data, err := o.readFile(name)
+		if err != nil {
+			return err
+		}
+
+		_ = o.upper.RemoveAll(newname)
+
+		return o.upper.WriteFile(newname, data, info.Mode().Perm())
+	}
+
+	if err := o.upper.Mkdir(newname, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	entries, err := o.readDir(name)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if err := o.copyTo(path.Join(newname, e.Name()), path.Join(name, e.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *Overlay) removeAll(name string) error {
+	if err := o.upper.RemoveAll(name); err != nil {
+		return err
+	}
+
+	o.removed[name] = true
+
+	return nil
+}
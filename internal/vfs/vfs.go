@@ -0,0 +1,135 @@
+// Package vfs abstracts the file systems projects are generated into : the disk, memory, or an overlay of memory
+// over another file system.
+package vfs
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// FS is a file system a project can be generated into. Names are absolute paths, or relative to the working
+// directory on the disk.
+type FS interface {
+	Stat(name string) (os.FileInfo, error)
+	Lstat(name string) (os.FileInfo, error)
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte, perm os.FileMode) error
+	ReadDir(name string) ([]os.FileInfo, error)
+	Mkdir(name string, perm os.FileMode) error
+	MkdirAll(name string, perm os.FileMode) error
+
+	// MkdirTemp creates a new temporary directory, whose name begins with pattern, and returns its name
+	MkdirTemp(pattern string) (string, error)
+
+	Chmod(name string, mode os.FileMode) error
+
+	// Rename moves a file or a directory. The new name must not be an existing directory.
+	Rename(oldname, newname string) error
+
+	Remove(name string) error
+	RemoveAll(name string) error
+}
+
+// Exists returns whether the given file or directory exists
+func Exists(fs FS, name string) (bool, error) {
+	_, err := fs.Stat(name)
+	if err == nil {
+		return true, nil
+	}
+
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+// Copy copies a file or a directory and its content from a file system to another, keeping their permissions.
+// Existing files are overwritten.
+func Copy(dst FS, dstName string, src FS, srcName string) error {
+	info, err := src.Stat(srcName)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		data, err := src.ReadFile(srcName)
+		if err != nil {
+			return err
+		}
+
+		if err := dst.WriteFile(dstName, data, info.Mode().Perm()); err != nil {
+			return err
+		}
+
+		// The file may have existed with other permissions
+		return dst.Chmod(dstName, info.Mode().Perm())
+	}
+
+	if err := dst.MkdirAll(dstName, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	entries, err := src.ReadDir(srcName)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if err := Copy(dst, filepath.Join(dstName, e.Name()), src, filepath.Join(srcName, e.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// clean returns the canonical form of a name in memory file systems
+func clean(name string) string {
+	return path.Clean(filepath.ToSlash(name))
+}
+
+// isRoot returns whether the cleaned name is the root of a memory file system, which always exists
+func isRoot(name string) bool {
+	return name == "/" || name == "."
+}
+
+// Walk walks the file tree rooted at root, calling fn for each file or directory in lexical order, as filepath.Walk
+// does on the disk
+func Walk(fs FS, root string, fn filepath.WalkFunc) error {
+	info, err := fs.Lstat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walk(fs, root, info, fn)
+	}
+
+	if err == filepath.SkipDir {
+		return nil
+	}
+
+	return err
+}
+
+func walk(fs FS, name string, info os.FileInfo, fn filepath.WalkFunc) error {
+	if !info.IsDir() {
+		return fn(name, info, nil)
+	}
+
+	entries, err := fs.ReadDir(name)
+
+	if err := fn(name, info, err); err != nil || entries == nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if err := walk(fs, filepath.Join(name, e.Name()), e, fn); err != nil {
+			if !e.IsDir() || err != filepath.SkipDir {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
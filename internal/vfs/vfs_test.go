@@ -0,0 +1,328 @@
+package vfs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// names returns the names of the entries of the directory
+func names(t *testing.T, fs FS, dir string) []string {
+	t.Helper()
+
+	entries, err := fs.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir(%s) : %v", dir, err)
+	}
+
+	res := make([]string, len(entries))
+	for i, e := range entries {
+		res[i] = e.Name()
+	}
+
+	return res
+}
+
+func equalNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// content fails the test if the named file does not hold the expected content
+func content(t *testing.T, fs FS, name, want string) {
+	t.Helper()
+
+	got, err := fs.ReadFile(name)
+	if err != nil {
+		t.Fatalf("ReadFile(%s) : %v", name, err)
+	}
+
+	if string(got) != want {
+		t.Errorf("ReadFile(%s) = %q, want %q", name, got, want)
+	}
+}
+
+// missing fails the test if the named file exists
+func missing(t *testing.T, fs FS, name string) {
+	t.Helper()
+
+	if _, err := fs.Stat(name); !os.IsNotExist(err) {
+		t.Errorf("Stat(%s) : got %v, want a not exist error", name, err)
+	}
+}
+
+// newBase returns a memory file system holding /p/a, /p/dir/f and /p/dir/sub/g
+func newBase(t *testing.T) *Memory {
+	t.Helper()
+
+	m := NewMemory()
+
+	if err := m.MkdirAll("/p/dir/sub", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, data := range map[string]string{"/p/a": "a", "/p/dir/f": "f", "/p/dir/sub/g": "g"} {
+		if err := m.WriteFile(name, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return m
+}
+
+func TestMemory(t *testing.T) {
+	m := newBase(t)
+
+	if err := m.WriteFile("/nowhere/f", nil, 0644); !os.IsNotExist(err) {
+		t.Errorf("writing without parent : got %v, want a not exist error", err)
+	}
+
+	if err := m.Mkdir("/p/a", 0755); !os.IsExist(err) {
+		t.Errorf("Mkdir on a file : got %v, want an exist error", err)
+	}
+
+	if got, want := names(t, m, "/p"), []string{"a", "dir"}; !equalNames(got, want) {
+		t.Errorf("ReadDir = %v, want %v", got, want)
+	}
+
+	if err := m.Remove("/p/dir"); err == nil {
+		t.Error("removing a non empty directory succeeded")
+	}
+
+	if err := m.Rename("/p/dir", "/p/moved"); err != nil {
+		t.Fatal(err)
+	}
+
+	missing(t, m, "/p/dir/f")
+	content(t, m, "/p/moved/f", "f")
+	content(t, m, "/p/moved/sub/g", "g")
+
+	if err := m.Rename("/p/moved", "/p/moved/sub/x"); err == nil {
+		t.Error("renaming a directory into itself succeeded")
+	}
+
+	if err := m.RemoveAll("/p/moved"); err != nil {
+		t.Fatal(err)
+	}
+
+	missing(t, m, "/p/moved/sub/g")
+}
+
+func TestOverlayReadThrough(t *testing.T) {
+	base := newBase(t)
+	o := NewOverlay(base)
+
+	content(t, o, "/p/a", "a")
+
+	if err := o.WriteFile("/p/dir/new", []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := o.WriteFile("/p/a", []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	content(t, o, "/p/dir/new", "new")
+	content(t, o, "/p/a", "changed")
+
+	if got, want := names(t, o, "/p/dir"), []string{"f", "new", "sub"}; !equalNames(got, want) {
+		t.Errorf("ReadDir = %v, want %v", got, want)
+	}
+
+	// An existing file keeps its permissions
+	if info, err := o.Stat("/p/a"); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Stat(/p/a) = %v, %v, want mode 0600", info, err)
+	}
+
+	// The base is never written to
+	content(t, base, "/p/a", "a")
+	missing(t, base, "/p/dir/new")
+}
+
+func TestOverlayRemove(t *testing.T) {
+	base := newBase(t)
+	o := NewOverlay(base)
+
+	if err := o.Remove("/p/dir"); err == nil {
+		t.Error("removing a non empty directory succeeded")
+	}
+
+	if err := o.Remove("/p/a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := o.RemoveAll("/p/dir"); err != nil {
+		t.Fatal(err)
+	}
+
+	missing(t, o, "/p/a")
+	missing(t, o, "/p/dir")
+	missing(t, o, "/p/dir/sub/g")
+
+	if got := names(t, o, "/p"); len(got) != 0 {
+		t.Errorf("ReadDir = %v, want no entries", got)
+	}
+
+	content(t, base, "/p/a", "a")
+	content(t, base, "/p/dir/sub/g", "g")
+}
+
+func TestOverlayRecreate(t *testing.T) {
+	o := NewOverlay(newBase(t))
+
+	if err := o.RemoveAll("/p/dir"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := o.Remove("/p/a"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A directory created again does not show the content of the removed one
+	if err := o.Mkdir("/p/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := names(t, o, "/p/dir"); len(got) != 0 {
+		t.Errorf("ReadDir = %v, want no entries", got)
+	}
+
+	missing(t, o, "/p/dir/f")
+
+	if err := o.MkdirAll("/p/dir/sub", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	missing(t, o, "/p/dir/sub/g")
+
+	// A file created again has its new content
+	if err := o.WriteFile("/p/a", []byte("again"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	content(t, o, "/p/a", "again")
+
+	if got, want := names(t, o, "/p"), []string{"a", "dir"}; !equalNames(got, want) {
+		t.Errorf("ReadDir = %v, want %v", got, want)
+	}
+}
+
+func TestOverlayRename(t *testing.T) {
+	base := newBase(t)
+	o := NewOverlay(base)
+
+	if err := o.Rename("/p/dir", "/p/moved"); err != nil {
+		t.Fatal(err)
+	}
+
+	missing(t, o, "/p/dir")
+	content(t, o, "/p/moved/f", "f")
+	content(t, o, "/p/moved/sub/g", "g")
+
+	if err := o.Rename("/p/a", "/p/moved/a"); err != nil {
+		t.Fatal(err)
+	}
+
+	missing(t, o, "/p/a")
+	content(t, o, "/p/moved/a", "a")
+
+	if err := o.Rename("/p/moved", "/p/moved/sub/x"); err == nil {
+		t.Error("renaming a directory into itself succeeded")
+	}
+
+	if err := o.Mkdir("/p/other", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := o.Rename("/p/moved", "/p/other"); err == nil {
+		t.Error("renaming onto an existing directory succeeded")
+	}
+
+	// The old name can be used again
+	if err := o.Rename("/p/moved", "/p/dir"); err != nil {
+		t.Fatal(err)
+	}
+
+	content(t, o, "/p/dir/a", "a")
+	content(t, o, "/p/dir/sub/g", "g")
+	missing(t, o, "/p/moved")
+
+	content(t, base, "/p/a", "a")
+	content(t, base, "/p/dir/f", "f")
+	missing(t, base, "/p/moved")
+}
+
+func TestOverlayChmod(t *testing.T) {
+	base := newBase(t)
+	o := NewOverlay(base)
+
+	if err := o.Chmod("/p/dir/f", 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, err := o.Stat("/p/dir/f"); err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("Stat = %v, %v, want mode 0644", info, err)
+	}
+
+	if info, err := base.Stat("/p/dir/f"); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("base Stat = %v, %v, want mode 0600", info, err)
+	}
+
+	// Copying up the file keeps the rest of the directory visible
+	if got, want := names(t, o, "/p/dir"), []string{"f", "sub"}; !equalNames(got, want) {
+		t.Errorf("ReadDir = %v, want %v", got, want)
+	}
+}
+
+func TestCopy(t *testing.T) {
+	m := NewMemory()
+
+	if err := Copy(m, "/copy", newBase(t), "/p"); err != nil {
+		t.Fatal(err)
+	}
+
+	content(t, m, "/copy/a", "a")
+	content(t, m, "/copy/dir/f", "f")
+	content(t, m, "/copy/dir/sub/g", "g")
+
+	if info, err := m.Stat("/copy/dir/sub/g"); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Stat = %v, %v, want mode 0600", info, err)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	m := newBase(t)
+
+	var walked []string
+
+	err := Walk(m, "/p", func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		walked = append(walked, filepath.ToSlash(name))
+
+		if info.IsDir() && info.Name() == "sub" {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"/p", "/p/a", "/p/dir", "/p/dir/f", "/p/dir/sub"}
+	if !equalNames(walked, want) {
+		t.Errorf("Walk = %v, want %v", walked, want)
+	}
+}